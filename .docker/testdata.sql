@@ -54,6 +54,9 @@ INSERT INTO "public"."users" ("id", "name", "email") VALUES
 ('3', 'user03', 'user03@email.com'),
 ('10', NULL, 'user03@email.com');
 
+-- the rows inserted without an id get one past the seeded ones
+SELECT setval('users_id_seq', 10);
+
 
 DROP TABLE IF EXISTS "public"."address";
 
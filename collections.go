@@ -0,0 +1,141 @@
+package pgxscan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+)
+
+// isCollection reports whether i is a pointer to a slice or a map filled with
+// one element per row, rather than a slice of structs or a single value like
+// []byte.
+func isCollection(i interface{}) bool {
+	t := reflect.TypeOf(i)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Slice:
+		elem := t.Elem()
+		if elem.Kind() == reflect.Uint8 {
+			return false
+		}
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		return !sqlmaper.IsStruct(elem.Kind()) || sqlmaper.ImplementsScanner(elem)
+	}
+	return false
+}
+
+var defaultConnInfo = pgtype.NewConnInfo()
+
+// isSliceColumn reports whether a single value of the postgres type oid is
+// scanned into a Go slice: arrays, json and bytea.
+func isSliceColumn(oid uint32) bool {
+	switch oid {
+	case pgtype.JSONOID, pgtype.JSONBOID, pgtype.ByteaOID:
+		return true
+	}
+	dt, ok := defaultConnInfo.DataTypeForOID(oid)
+	return ok && strings.HasPrefix(dt.Name, "_")
+}
+
+// holdsColumn reports whether the elements of type t of a slice hold a whole
+// array, json or bytea column, being slices or arrays themselves.
+func holdsColumn(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+}
+
+// scanCollection scans every row into i, a pointer to a slice or a map.
+//
+// A slice gets one element per row of a single column result, like
+// `SELECT id FROM users` into a []int64. When that column is an array, json
+// or bytea and the elements of the slice are scalars, the value of the column
+// is scanned into the slice instead, like it is when scanning a single value.
+// A slice of slices, like a [][]byte or a [][]string, still gets one element
+// per row.
+//
+// A map gets one entry per row of a two columns result, the first column
+// being the key and the second the value, like `SELECT id, name FROM users`
+// into a map[int64]string. A map[string]interface{} is a record instead, see
+// scanRecords.
+//
+// An empty result leaves i empty and returns nil, like scanning values, rather
+// than pgx.ErrNoRows.
+func (r *rows) scanCollection(i interface{}) error {
+	val, err := validate(i)
+	if err != nil {
+		return err
+	}
+	fields := r.rows.FieldDescriptions()
+	if val.Kind() == reflect.Slice && len(fields) == 1 && isSliceColumn(fields[0].DataTypeOID) && !holdsColumn(val.Type().Elem()) {
+		return r.ScanVal(i)
+	}
+
+	defer r.Close()
+	switch val.Kind() {
+	case reflect.Slice:
+		if len(fields) != 1 {
+			return fmt.Errorf("scanning into %v requires a single column, got %d", val.Type(), len(fields))
+		}
+		elemType := val.Type().Elem()
+		for r.Next() {
+			elem := reflect.New(elemType)
+			if err := r.rows.Scan(elem.Interface()); err != nil {
+				return r.valueScanError(err, elemType)
+			}
+			val.Set(reflect.Append(val, elem.Elem()))
+		}
+	case reflect.Map:
+		if len(fields) != 2 {
+			return fmt.Errorf("scanning into %v requires two columns, the key and the value, got %d", val.Type(), len(fields))
+		}
+		if val.IsNil() {
+			val.Set(reflect.MakeMap(val.Type()))
+		}
+		keyType, elemType := val.Type().Key(), val.Type().Elem()
+		for r.Next() {
+			key, elem := reflect.New(keyType), reflect.New(elemType)
+			if err := r.rows.Scan(key.Interface(), elem.Interface()); err != nil {
+				return r.valueScanError(err, keyType, elemType)
+			}
+			val.SetMapIndex(key.Elem(), elem.Elem())
+		}
+	}
+	return r.Err()
+}
+
+// valueScanError turns the pgx error of a scan into values of types into a
+// ScanError.
+func (r *rows) valueScanError(err error, types ...reflect.Type) error {
+	var argErr pgx.ScanArgError
+	if !errors.As(err, &argErr) {
+		return err
+	}
+	fields := r.rows.FieldDescriptions()
+	idx := argErr.ColumnIndex
+	if idx < 0 || idx >= len(fields) || idx >= len(types) {
+		return err
+	}
+	return &ScanError{
+		Column:      string(fields[idx].Name),
+		Ordinal:     idx,
+		DataTypeOID: fields[idx].DataTypeOID,
+		GoType:      types[idx],
+		Err:         argErr.Err,
+	}
+}
@@ -0,0 +1,49 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/randallmlough/pgxscan"
+	"github.com/randallmlough/pgxscan/testdata"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rows_ScanSliceOfScalars(t *testing.T) {
+	rows, err := newTestDB(t).Query(context.Background(), `SELECT "id" FROM "users" ORDER BY "id"`)
+	require.NoError(t, err)
+
+	var ids []int64
+	require.NoError(t, pgxscan.NewScanner(rows).Scan(&ids))
+	require.Equal(t, []int64{1, 2, 3, 10}, ids)
+
+	rows, err = newTestDB(t).Query(context.Background(), `SELECT "name" FROM "users" ORDER BY "id"`)
+	require.NoError(t, err)
+
+	var names []*string
+	require.NoError(t, pgxscan.NewScanner(rows).Scan(&names))
+	require.Len(t, names, 4)
+	require.Equal(t, "user01", *names[0])
+	require.Nil(t, names[3])
+}
+
+func Test_rows_ScanArrayColumnIntoSlice(t *testing.T) {
+	rows, err := newTestDB(t).Query(context.Background(), `SELECT "string_slice" FROM "test" WHERE "id" = $1`, 1)
+	require.NoError(t, err)
+
+	var dst []string
+	require.NoError(t, pgxscan.NewScanner(rows).Scan(&dst))
+	require.Equal(t, testdata.TestRow1.StringSlice, dst)
+}
+
+func Test_rows_ScanMap(t *testing.T) {
+	rows, err := newTestDB(t).Query(context.Background(), `SELECT "id", "email" FROM "users" WHERE "id" < $1`, 3)
+	require.NoError(t, err)
+
+	var emails map[int32]string
+	require.NoError(t, pgxscan.NewScanner(rows).Scan(&emails))
+	require.Equal(t, map[int32]string{1: "user01@email.com", 2: "user02@email.com"}, emails)
+}
@@ -0,0 +1,125 @@
+package pgxscan
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_isCollection(t *testing.T) {
+	type User struct {
+		ID int64
+	}
+	tests := []struct {
+		name string
+		test interface{}
+		want bool
+	}{
+		{name: "slice of int64", test: &[]int64{}, want: true},
+		{name: "slice of pointers to string", test: &[]*string{}, want: true},
+		{name: "slice of scanner structs", test: &[]pgtype.UUID{}, want: true},
+		{name: "slice of arrays", test: &[][16]byte{}, want: true},
+		{name: "map", test: &map[int64]string{}, want: true},
+		{name: "bytes", test: &[]byte{}, want: false},
+		{name: "slice of structs", test: &[]User{}, want: false},
+		{name: "slice of pointers to structs", test: &[]*User{}, want: false},
+		{name: "non pointer slice", test: []int64{}, want: false},
+		{name: "int64", test: new(int64), want: false},
+		{name: "nil", test: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, isCollection(tt.test))
+		})
+	}
+}
+
+func Test_rows_ScanSliceOfScalars(t *testing.T) {
+	src := newFakeRows([]string{"id"}, []interface{}{int64(1)}, []interface{}{int64(2)}, []interface{}{int64(3)})
+
+	var ids []int64
+	err := NewScanner(src).Scan(&ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 3}, ids)
+	require.True(t, src.closed)
+
+	var names []*string
+	err = NewScanner(newFakeRows([]string{"name"}, []interface{}{"user01"}, []interface{}{nil})).Scan(&names)
+	require.NoError(t, err)
+	require.Equal(t, []*string{stringToPtr("user01"), nil}, names)
+}
+
+func Test_rows_ScanSliceOfScalars_NoRows(t *testing.T) {
+	// like scanning values, an empty result is not an error
+	var ids []int64
+	err := NewScanner(newFakeRows([]string{"id"})).Scan(&ids)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	var names map[int64]string
+	err = NewScanner(newFakeRows([]string{"id", "name"})).Scan(&names)
+	require.NoError(t, err)
+	require.Empty(t, names)
+}
+
+func Test_rows_ScanSliceOfScalars_ArrayColumn(t *testing.T) {
+	src := newFakeRows([]string{"string_slice"}, []interface{}{[]string{"cats", "dogs"}})
+	src.fields[0].DataTypeOID = pgtype.TextArrayOID
+
+	var dst []string
+	err := NewScanner(src).Scan(&dst)
+	require.NoError(t, err)
+	require.Equal(t, []string{"cats", "dogs"}, dst)
+}
+
+func Test_rows_ScanSliceOfSlices(t *testing.T) {
+	src := newFakeRows([]string{"data"}, []interface{}{[]byte("blob01")}, []interface{}{[]byte("blob02")})
+	src.fields[0].DataTypeOID = pgtype.ByteaOID
+	var blobs [][]byte
+	require.NoError(t, NewScanner(src).Scan(&blobs))
+	require.Equal(t, [][]byte{[]byte("blob01"), []byte("blob02")}, blobs)
+
+	src = newFakeRows([]string{"tags"}, []interface{}{[]string{"cats", "dogs"}}, []interface{}{[]string{"birds"}})
+	src.fields[0].DataTypeOID = pgtype.TextArrayOID
+	var tags [][]string
+	require.NoError(t, NewScanner(src).Scan(&tags))
+	require.Equal(t, [][]string{{"cats", "dogs"}, {"birds"}}, tags)
+}
+
+func Test_rows_ScanSliceOfScalars_WantErr(t *testing.T) {
+	var ids []int64
+	err := NewScanner(newGenericUserRows()).Scan(&ids)
+	require.EqualError(t, err, "scanning into []int64 requires a single column, got 2")
+
+	src := newFakeRows([]string{"id"}, []interface{}{int64(1)}, []interface{}{nil})
+	err = NewScanner(src).Scan(&ids)
+	require.EqualError(t, err, "can't scan into dest[0] (field 'id'): cannot assign NULL to *int64")
+	var scanErr *ScanError
+	require.True(t, errors.As(err, &scanErr))
+	require.Equal(t, reflect.TypeOf(int64(0)), scanErr.GoType)
+	require.Equal(t, uint32(pgtype.Int8OID), scanErr.DataTypeOID)
+}
+
+func Test_rows_ScanMap(t *testing.T) {
+	src := newGenericUserRows()
+
+	var names map[int64]string
+	err := NewScanner(src).Scan(&names)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]string{1: "user01", 2: "user02"}, names)
+	require.True(t, src.closed)
+
+	existing := map[int64]string{3: "user03"}
+	err = NewScanner(newGenericUserRows()).Scan(&existing)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]string{1: "user01", 2: "user02", 3: "user03"}, existing)
+}
+
+func Test_rows_ScanMap_WantErr(t *testing.T) {
+	var names map[int64]string
+	err := NewScanner(newFakeRows([]string{"id"}, []interface{}{int64(1)})).Scan(&names)
+	require.EqualError(t, err, "scanning into map[int64]string requires two columns, the key and the value, got 1")
+}
@@ -0,0 +1,225 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Aliases holds the table alias of the columns of nested struct fields by
+// their notated prefix, like "address" or "orders.items". The columns of a
+// prefix without an alias are not qualified.
+type Aliases map[string]string
+
+// SelectColumns returns the SELECT list of the columns of v, a struct or a
+// pointer to one, using DefaultMapper. See Mapper.SelectColumns.
+//
+//	cols, err := pgxscan.SelectColumns(User{}, "u", pgxscan.Aliases{"address": "a"})
+//	rows, err := conn.Query(ctx, `SELECT `+cols+` FROM users u JOIN addresses a ON a.user_id = u.id`)
+func SelectColumns(v interface{}, alias string, nested ...Aliases) (string, error) {
+	return DefaultMapper.SelectColumns(v, alias, nested...)
+}
+
+// SelectColumns returns the SELECT list of the columns of v, a struct or a
+// pointer to one, in field order and qualified with alias:
+//
+//	"u"."id", "u"."name"
+//
+// The columns of nested notated structs and of the elements of many fields
+// follow the ones of v, each prefix starting with the notate column described
+// in GetColumnNames and qualified with its alias in nested:
+//
+//	"u"."id", "u"."name", 0 AS "notate:address", "a"."id", "a"."city"
+func (m *Mapper) SelectColumns(v interface{}, alias string, nested ...Aliases) (string, error) {
+	cols, err := m.structColumns(v, true)
+	if err != nil {
+		return "", err
+	}
+	aliases := Aliases{"": alias}
+	for _, a := range nested {
+		for prefix, alias := range a {
+			aliases[prefix] = alias
+		}
+	}
+	list := make([]string, 0, len(cols))
+	prefix := ""
+	for _, col := range cols {
+		if col.prefix != prefix {
+			prefix = col.prefix
+			list = append(list, "0 AS "+pgx.Identifier{m.notatePrefix() + prefix}.Sanitize())
+		}
+		list = append(list, qualify(aliases[prefix], col.name))
+	}
+	return strings.Join(list, ", "), nil
+}
+
+// InsertColumns returns the column list and the values list of an INSERT of
+// v, a struct or a pointer to one, using DefaultMapper. See
+// Mapper.InsertColumns.
+func InsertColumns(v interface{}) (columns, values string, err error) {
+	return DefaultMapper.InsertColumns(v)
+}
+
+// InsertColumns returns the column list and the values list of an INSERT of
+// v, a struct or a pointer to one. Only the columns of v itself are listed,
+// the ones of nested notated structs and many fields come from other tables,
+// and the fields with the readonly or generated tag option are left out.
+// The values are the named placeholders of the columns, so the statement can
+// be run with NamedExec:
+//
+//	cols, values, err := pgxscan.InsertColumns(user)
+//	_, err = pgxscan.NamedExec(ctx, conn, `INSERT INTO users (`+cols+`) VALUES (`+values+`)`, user)
+func (m *Mapper) InsertColumns(v interface{}) (columns, values string, err error) {
+	cols, err := m.structColumns(v, false)
+	if err != nil {
+		return "", "", err
+	}
+	names := make([]string, 0, len(cols))
+	params := make([]string, 0, len(cols))
+	for _, col := range cols {
+		if col.prefix == "" && !col.readOnly {
+			names = append(names, qualify("", col.name))
+			params = append(params, ":"+col.name)
+		}
+	}
+	return strings.Join(names, ", "), strings.Join(params, ", "), nil
+}
+
+// ReturningColumns returns the RETURNING list of the columns of v, a struct or
+// a pointer to one, using DefaultMapper. See Mapper.ReturningColumns.
+func ReturningColumns(v interface{}) (string, error) {
+	return DefaultMapper.ReturningColumns(v)
+}
+
+// ReturningColumns returns the RETURNING list of the columns of v, a struct or
+// a pointer to one. Like InsertColumns, only the columns of v itself are
+// listed, including the readonly and generated ones.
+//
+//	cols, values, _ := pgxscan.InsertColumns(user)
+//	returning, _ := pgxscan.ReturningColumns(user)
+//	rows, err := pgxscan.NamedQuery(ctx, conn, `INSERT INTO users (`+cols+`) VALUES (`+values+`) RETURNING `+returning, user)
+func (m *Mapper) ReturningColumns(v interface{}) (string, error) {
+	cols, err := m.structColumns(v, false)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		if col.prefix == "" {
+			names = append(names, qualify("", col.name))
+		}
+	}
+	return strings.Join(names, ", "), nil
+}
+
+// structColumn is a column of a struct type, split into its notated prefix
+// and its name.
+type structColumn struct {
+	prefix string
+	name   string
+	// index is the path to the field, through the elements of many fields,
+	// the columns are listed in its order.
+	index    []int
+	readOnly bool
+}
+
+// structColumns returns the columns of v, a struct or a pointer to one,
+// ordered by field with the columns of v first and then by prefix. The
+// columns of the elements of many fields are listed when many is set.
+func (m *Mapper) structColumns(v interface{}, many bool) ([]structColumn, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot list the columns of %T: expecting a struct", v)
+	}
+	cols, err := m.appendColumns(nil, []reflect.Type{t}, "", nil, many)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(cols, func(i, j int) bool {
+		return lessIndex(cols[i].index, cols[j].index)
+	})
+	// prefixes are listed in the order of their first column, after the
+	// columns of v which are not notated
+	first := map[string]int{"": -1}
+	for i, col := range cols {
+		if _, ok := first[col.prefix]; !ok {
+			first[col.prefix] = i
+		}
+	}
+	sort.SliceStable(cols, func(i, j int) bool {
+		return first[cols[i].prefix] < first[cols[j].prefix]
+	})
+	return cols, nil
+}
+
+// appendColumns appends the columns of the last struct type of path to cols.
+// The many fields of a type already in path are skipped, they would be
+// listed forever.
+func (m *Mapper) appendColumns(cols []structColumn, path []reflect.Type, prefix string, index []int, many bool) ([]structColumn, error) {
+	cm, err := m.columnMap(path[len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	for key, data := range cm {
+		if data.Inline {
+			// the columns of inline maps are not known in advance
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), data.FieldIndex...)
+		if data.Many {
+			if !many {
+				continue
+			}
+			elem := data.GoType.Elem()
+			if elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			if containsType(path, elem) {
+				continue
+			}
+			if cols, err = m.appendColumns(cols, append(path[:len(path):len(path)], elem), prefix+key+".", fieldIndex, many); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		col := structColumn{name: prefix + key, index: fieldIndex, readOnly: data.ReadOnly}
+		if dot := strings.LastIndexByte(col.name, '.'); dot >= 0 {
+			col.prefix, col.name = col.name[:dot], col.name[dot+1:]
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+func containsType(list []reflect.Type, t reflect.Type) bool {
+	for _, v := range list {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// lessIndex reports whether the field at index a is declared before the one at b.
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// qualify returns the quoted column name, qualified with alias unless it is empty.
+func qualify(alias, name string) string {
+	if alias == "" {
+		return pgx.Identifier{name}.Sanitize()
+	}
+	return pgx.Identifier{alias, name}.Sanitize()
+}
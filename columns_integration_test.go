@@ -0,0 +1,57 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SelectColumns(t *testing.T) {
+	type (
+		Address struct {
+			ID    uint32
+			Line1 string `db:"line_1"`
+			City  string
+		}
+		User struct {
+			ID      uint32
+			Name    string
+			Address *Address `db:"address,notate"`
+		}
+	)
+	cols, err := pgxscan.SelectColumns(User{}, "u", pgxscan.Aliases{"address": "a"})
+	require.NoError(t, err)
+	rows, err := newTestDB(t).Query(context.Background(), `SELECT `+cols+` FROM "users" "u" JOIN "address" "a" ON "a"."user_id" = "u"."id" WHERE "u"."id" = $1`, 1)
+	require.NoError(t, err)
+
+	u, err := pgxscan.One[User](rows)
+	require.NoError(t, err)
+	require.Equal(t, User{ID: 1, Name: "user01", Address: &Address{ID: 1, Line1: "line01_user01", City: "city01"}}, u)
+}
+
+func Test_InsertColumns(t *testing.T) {
+	tx, err := testDB.Begin(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+
+	type User struct {
+		Name  string
+		Email string
+	}
+	in := User{Name: "user05", Email: "user05@email.com"}
+	cols, values, err := pgxscan.InsertColumns(in)
+	require.NoError(t, err)
+	returning, err := pgxscan.ReturningColumns(in)
+	require.NoError(t, err)
+
+	rows, err := pgxscan.NamedQuery(context.Background(), tx, `INSERT INTO "users" (`+cols+`) VALUES (`+values+`) RETURNING `+returning, in)
+	require.NoError(t, err)
+	out, err := pgxscan.One[User](rows)
+	require.NoError(t, err)
+	require.Equal(t, in, out)
+}
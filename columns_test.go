@@ -0,0 +1,72 @@
+package pgxscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SelectColumns(t *testing.T) {
+	type (
+		Base struct {
+			ID int64 `db:"id"`
+		}
+		Address struct {
+			ID   int64
+			City string
+		}
+		User struct {
+			Base
+			Name    string
+			Address *Address `db:"address,notate"`
+			Email   string
+		}
+	)
+	cols, err := SelectColumns(User{}, "u", Aliases{"address": "a"})
+	require.NoError(t, err)
+	require.Equal(t, `"u"."id", "u"."name", "u"."email", 0 AS "notate:address", "a"."id", "a"."city"`, cols)
+
+	cols, err = SelectColumns(&User{}, "")
+	require.NoError(t, err)
+	require.Equal(t, `"id", "name", "email", 0 AS "notate:address", "id", "city"`, cols)
+
+	// the generated list is mapped back to the struct
+	src := newFakeRows([]string{"id", "name", "email", "notate:address", "id", "city"},
+		[]interface{}{int64(1), "user01", "user01@email.com", 0, int64(2), "city01"},
+	)
+	var user User
+	require.NoError(t, ScanOne(src, &user))
+	require.Equal(t, User{Base: Base{ID: 1}, Name: "user01", Email: "user01@email.com", Address: &Address{ID: 2, City: "city01"}}, user)
+}
+
+func Test_SelectColumns_Many(t *testing.T) {
+	cols, err := SelectColumns(manyUser{}, "u", Aliases{"orders": "o", "orders.items": "i"})
+	require.NoError(t, err)
+	require.Equal(t, `"u"."id", "u"."name", 0 AS "notate:orders", "o"."id", "o"."total", 0 AS "notate:orders.items", "i"."id", "i"."name"`, cols)
+}
+
+func Test_InsertColumns(t *testing.T) {
+	cols, values, err := InsertColumns(&manyUser{})
+	require.NoError(t, err)
+	require.Equal(t, `"id", "name"`, cols)
+	require.Equal(t, `:id, :name`, values)
+
+	returning, err := ReturningColumns(namedUser{})
+	require.NoError(t, err)
+	require.Equal(t, `"id", "name"`, returning)
+}
+
+func Test_SelectColumns_WantErr(t *testing.T) {
+	_, err := SelectColumns(1, "u")
+	require.EqualError(t, err, "cannot list the columns of int: expecting a struct")
+}
+
+func Test_SelectColumns_SelfReferentialMany(t *testing.T) {
+	type Employee struct {
+		ID      int64      `db:"id,pk"`
+		Reports []Employee `db:"reports,many"`
+	}
+	cols, err := SelectColumns(Employee{}, "e")
+	require.NoError(t, err)
+	require.Equal(t, `"e"."id"`, cols)
+}
@@ -0,0 +1,202 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// OID is a postgres type OID a converter is registered from, like
+// OID(pgtype.NumericOID). The pgtype constants are untyped, so they must be
+// converted for RegisterConverter to tell them from the Go type int.
+type OID uint32
+
+// ConvertFunc converts src, the non NULL value of a column, into a value
+// assignable or convertible to the field it is scanned into.
+type ConvertFunc func(src interface{}) (interface{}, error)
+
+// convOption names the converter of a field, like `db:"price,conv=cents"`.
+const convOption = "conv"
+
+// converter is a ConvertFunc registered for the columns of a type oid, or
+// scanned into Go type from when oid is 0, and the fields of type to.
+type converter struct {
+	name string
+	oid  uint32
+	from reflect.Type
+	to   reflect.Type
+	fn   ConvertFunc
+}
+
+// RegisterConverter registers fn on DefaultMapper. See Mapper.RegisterConverter.
+func RegisterConverter(from, to interface{}, fn ConvertFunc) {
+	DefaultMapper.RegisterConverter(from, to, fn)
+}
+
+// RegisterNamedConverter registers fn on DefaultMapper. See
+// Mapper.RegisterNamedConverter.
+func RegisterNamedConverter(name string, from, to interface{}, fn ConvertFunc) {
+	DefaultMapper.RegisterNamedConverter(name, from, to, fn)
+}
+
+// RegisterConverter registers fn to scan the fields of type to, or of a
+// pointer to it, which implement neither sql.Scanner nor a pgx decoder.
+//
+// from is a postgres type OID, given as an OID or a uint32, or a Go type,
+// given as a reflect.Type or a value of that type. The columns of type OID
+// from are decoded to the values they have in a map[string]interface{}
+// record, like int64 or string, before fn converts them. Otherwise the column
+// is first scanned into the Go type from by pgx. A converter registered from
+// an OID is preferred for the columns of that type, it is not used by
+// ScanStruct which does not know the column types.
+//
+// to is a reflect.Type or a value of that type. A NULL column sets the field
+// to its zero value without calling fn.
+//
+//	pgxscan.RegisterConverter(pgxscan.OID(pgtype.NumericOID), Money(0), func(src interface{}) (interface{}, error) {
+//	    return ParseMoney(src.(string))
+//	})
+//
+// Converters should be registered before scanning, the fields of a struct
+// already scanned use the converters registered at that time.
+func (m *Mapper) RegisterConverter(from, to interface{}, fn ConvertFunc) {
+	m.register(newConverter("", from, to, fn))
+}
+
+// RegisterNamedConverter is like RegisterConverter, the converter is only used
+// by the fields naming it with the conv tag option, like
+// `db:"price,conv=cents"` or `scan:"conv=cents"`, whatever the column type
+// is.
+func (m *Mapper) RegisterNamedConverter(name string, from, to interface{}, fn ConvertFunc) {
+	m.register(newConverter(name, from, to, fn))
+}
+
+func newConverter(name string, from, to interface{}, fn ConvertFunc) *converter {
+	c := &converter{name: name, to: typeOf(to), fn: fn}
+	switch from := from.(type) {
+	case OID:
+		c.oid = uint32(from)
+	case uint32:
+		c.oid = from
+	default:
+		c.from = typeOf(from)
+	}
+	if c.from == nil {
+		c.from = recordValueType(c.oid)
+	}
+	return c
+}
+
+// typeOf returns v when it is a reflect.Type and its type otherwise.
+func typeOf(v interface{}) reflect.Type {
+	if t, ok := v.(reflect.Type); ok {
+		return t
+	}
+	return reflect.TypeOf(v)
+}
+
+func (m *Mapper) register(c *converter) {
+	m.convMu.Lock()
+	defer m.convMu.Unlock()
+	if c.name != "" {
+		if m.named == nil {
+			m.named = map[string]*converter{}
+		}
+		m.named[c.name] = c
+	} else {
+		// the latest converter of a type is preferred
+		m.converters = append([]*converter{c}, m.converters...)
+	}
+	// the plans compiled so far have the previous converters
+	m.clearPlans()
+}
+
+// fieldConverters returns the converters of field f of type t: the one it
+// names with the conv tag option, or the ones registered to its type.
+func (m *Mapper) fieldConverters(f reflect.StructField, t reflect.Type) ([]*converter, error) {
+	m.convMu.RLock()
+	defer m.convMu.RUnlock()
+	if name, ok := m.mapper.FieldOptions(f).Value(convOption); ok {
+		c, ok := m.named[name]
+		if !ok || !c.converts(t) {
+			return nil, fmt.Errorf(`converter "%s" of field %s is not registered for %v`, name, f.Name, t)
+		}
+		return []*converter{c}, nil
+	}
+	var convs []*converter
+	for _, c := range m.converters {
+		if c.converts(t) {
+			convs = append(convs, c)
+		}
+	}
+	return convs, nil
+}
+
+// converts reports whether c converts the values of the fields of type t.
+func (c *converter) converts(t reflect.Type) bool {
+	return t == c.to || (t.Kind() == reflect.Ptr && t.Elem() == c.to)
+}
+
+// pickConverter returns the converter of convs used for a column of type oid,
+// 0 when unknown: the one registered for oid, or the first one registered for
+// a Go type. A single converter named by a field is always used.
+func pickConverter(convs []*converter, oid uint32) *converter {
+	if len(convs) == 1 && convs[0].name != "" {
+		return convs[0]
+	}
+	var fallback *converter
+	for _, c := range convs {
+		switch {
+		case c.oid != 0 && c.oid == oid:
+			return c
+		case c.oid == 0 && fallback == nil:
+			fallback = c
+		}
+	}
+	return fallback
+}
+
+// newHolder returns the pointer the column is scanned into before being
+// converted.
+func (c *converter) newHolder() reflect.Value {
+	return newRecordHolder(c.from)
+}
+
+// assign sets f to the converted value scanned into holder, or to its zero
+// value when the column is NULL.
+func (c *converter) assign(f, holder reflect.Value) error {
+	src, err := recordValue(holder, c.oid != 0 && isJSONColumn(c.oid))
+	if err != nil {
+		return err
+	}
+	if src == nil {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+	dst, err := c.fn(src)
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(dst)
+	if !v.IsValid() {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+	t := f.Type()
+	if t.Kind() == reflect.Ptr && t.Elem() == c.to {
+		t = c.to
+	}
+	switch {
+	case v.Type().AssignableTo(t):
+	case v.Type().ConvertibleTo(t):
+		v = v.Convert(t)
+	default:
+		return fmt.Errorf("converter returned %v, expecting %v", v.Type(), t)
+	}
+	if t != f.Type() {
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		v = p
+	}
+	f.Set(v)
+	return nil
+}
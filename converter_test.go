@@ -0,0 +1,109 @@
+package pgxscan
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// convMoney holds cents, it is scanned from numeric columns like "12.50".
+	convMoney int64
+	// convEmail is scanned from text columns, lower cased.
+	convEmail struct {
+		Local, Domain string
+	}
+	convAddress struct {
+		City  string
+		Email convEmail
+	}
+	convUser struct {
+		ID       int64
+		Balance  convMoney
+		Email    *convEmail
+		Level    convMoney    `db:"level,conv=level"`
+		Address  *convAddress `db:"address,notate"`
+		Comments int64
+	}
+)
+
+func parseMoney(src interface{}) (interface{}, error) {
+	parts := strings.SplitN(src.(string), ".", 2)
+	units, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	cents := int64(0)
+	if len(parts) == 2 {
+		if cents, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return convMoney(units*100 + cents), nil
+}
+
+func parseEmail(src interface{}) (interface{}, error) {
+	parts := strings.SplitN(strings.ToLower(src.(string)), "@", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid email %q", src)
+	}
+	return convEmail{Local: parts[0], Domain: parts[1]}, nil
+}
+
+func newConvMapper() *Mapper {
+	mapper := NewMapper()
+	mapper.RegisterConverter(OID(pgtype.NumericOID), convMoney(0), parseMoney)
+	mapper.RegisterConverter("", convEmail{}, parseEmail)
+	mapper.RegisterNamedConverter("level", int64(0), convMoney(0), func(src interface{}) (interface{}, error) {
+		return src.(int64) * 1000, nil
+	})
+	return mapper
+}
+
+func newConvRows() *fakeRows {
+	src := newFakeRows([]string{"id", "balance", "email", "level", "comments", "notate:address", "city", "email"},
+		[]interface{}{int64(1), "12.50", "User01@Example.com", int64(2), int64(3), 0, "city01", "Contact@Example.com"},
+		[]interface{}{int64(2), nil, nil, nil, int64(0), 0, nil, nil},
+	)
+	src.fields[1].DataTypeOID = pgtype.NumericOID
+	return src
+}
+
+func Test_Mapper_RegisterConverter(t *testing.T) {
+	var users []convUser
+	require.NoError(t, NewScanner(newConvRows(), UseMapper(newConvMapper())).Scan(&users))
+	require.Equal(t, []convUser{
+		{
+			ID: 1, Balance: 1250, Email: &convEmail{Local: "user01", Domain: "example.com"}, Level: 2000,
+			Address:  &convAddress{City: "city01", Email: convEmail{Local: "contact", Domain: "example.com"}},
+			Comments: 3,
+		},
+		{ID: 2},
+	}, users)
+}
+
+func Test_Mapper_RegisterConverter_OIDMismatch(t *testing.T) {
+	// a numeric converter is not used for the columns of other types, which
+	// pgx scans as usual
+	src := newFakeRows([]string{"id", "balance"}, []interface{}{int64(1), int64(1250)})
+	var user convUser
+	require.NoError(t, NewScanner(src, UseMapper(newConvMapper())).Scan(&user))
+	require.Equal(t, convMoney(1250), user.Balance)
+}
+
+func Test_Mapper_RegisterConverter_WantErr(t *testing.T) {
+	var user convUser
+	err := NewScanner(newFakeRows([]string{"email"}, []interface{}{"user01"}), UseMapper(newConvMapper())).Scan(&user)
+	var scanErr *ScanError
+	require.True(t, errors.As(err, &scanErr))
+	require.Equal(t, "Email", scanErr.Field)
+	require.EqualError(t, scanErr.Err, `invalid email "user01"`)
+
+	err = NewScanner(newFakeRows([]string{"level"}, []interface{}{int64(1)})).Scan(&user)
+	require.EqualError(t, err, `converter "level" of field Level is not registered for pgxscan.convMoney`)
+}
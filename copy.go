@@ -0,0 +1,111 @@
+package pgxscan
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+)
+
+// Copier copies rows into a table with the COPY protocol through CopyFrom.
+type Copier interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// StructSource is a lazy sequence of T copied by CopyFromIter. Iter[T]
+// satisfies it, so the rows of a query can be copied without being held in
+// memory.
+type StructSource[T any] interface {
+	Next() bool
+	Value() T
+	Err() error
+}
+
+// CopyFromStructs copies src into table with the COPY protocol and returns
+// the number of rows copied. T is a struct or a pointer to one, its columns
+// are the ones InsertColumns lists: the columns of follow and embed fields
+// are flattened, and nested notated structs, many fields and fields with the
+// readonly or generated tag option are skipped. table can be qualified with
+// its schema, like "public.users".
+//
+//	n, err := pgxscan.CopyFromStructs(ctx, conn, "users", users)
+func CopyFromStructs[T any](ctx context.Context, c Copier, table string, src []T, opts ...Option) (int64, error) {
+	return CopyFromIter[T](ctx, c, table, &sliceSource[T]{values: src, idx: -1}, opts...)
+}
+
+// CopyFromIter is like CopyFromStructs, reading the values from src as they
+// are copied.
+//
+//	it := pgxscan.NewIter[User](rows)
+//	defer it.Close()
+//	n, err := pgxscan.CopyFromIter[User](ctx, conn, "users", it)
+func CopyFromIter[T any](ctx context.Context, c Copier, table string, src StructSource[T], opts ...Option) (int64, error) {
+	cfg := newConfig(opts...)
+	var zero T
+	cols, err := cfg.Mapper.structColumns(&zero, false)
+	if err != nil {
+		return 0, err
+	}
+	copySrc := &structCopySource[T]{src: src}
+	var names []string
+	for _, col := range cols {
+		if col.prefix == "" && !col.readOnly {
+			names = append(names, col.name)
+			copySrc.fields = append(copySrc.fields, col.index)
+		}
+	}
+	return c.CopyFrom(ctx, pgx.Identifier(strings.Split(table, ".")), names, copySrc)
+}
+
+// structCopySource is a pgx.CopyFromSource reading the fields of the values
+// of a StructSource.
+type structCopySource[T any] struct {
+	src    StructSource[T]
+	fields [][]int
+}
+
+var _ pgx.CopyFromSource = (*structCopySource[struct{}])(nil)
+
+func (s *structCopySource[T]) Next() bool {
+	return s.src.Next()
+}
+
+func (s *structCopySource[T]) Values() ([]interface{}, error) {
+	val := reflect.Indirect(reflect.ValueOf(s.src.Value()))
+	values := make([]interface{}, len(s.fields))
+	if !val.IsValid() {
+		// a nil pointer copies a row of NULL
+		return values, nil
+	}
+	for i, index := range s.fields {
+		if f, ok := sqlmaper.SafeGetFieldByIndex(val, index); ok {
+			values[i] = f.Interface()
+		}
+	}
+	return values, nil
+}
+
+func (s *structCopySource[T]) Err() error {
+	return s.src.Err()
+}
+
+// sliceSource is a StructSource over a slice.
+type sliceSource[T any] struct {
+	values []T
+	idx    int
+}
+
+func (s *sliceSource[T]) Next() bool {
+	s.idx++
+	return s.idx < len(s.values)
+}
+
+func (s *sliceSource[T]) Value() T {
+	return s.values[s.idx]
+}
+
+func (s *sliceSource[T]) Err() error {
+	return nil
+}
@@ -0,0 +1,56 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+type copyUser struct {
+	ID    uint32 `db:"id,generated"`
+	Name  string
+	Email string
+}
+
+func Test_CopyFromStructs(t *testing.T) {
+	tx, err := testDB.Begin(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+
+	n, err := pgxscan.CopyFromStructs(context.Background(), tx, "public.users", []copyUser{
+		{Name: "user05", Email: "user05@email.com"},
+		{Name: "user06", Email: "user06@email.com"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	var count int
+	err = pgxscan.Get(context.Background(), tx, &count, `SELECT COUNT(*) FROM "users" WHERE "name" IN ('user05', 'user06')`)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func Test_CopyFromIter(t *testing.T) {
+	tx, err := testDB.Begin(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+
+	rows, err := testDB.Query(context.Background(), `SELECT "id", "name" || '_copy' AS "name", "email" FROM "users" WHERE "name" IS NOT NULL ORDER BY "id"`)
+	require.NoError(t, err)
+	it := pgxscan.NewIter[copyUser](rows)
+	defer it.Close()
+
+	n, err := pgxscan.CopyFromIter[copyUser](context.Background(), tx, "users", it)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+
+	var names []string
+	err = pgxscan.Select(context.Background(), tx, &names, `SELECT "name" FROM "users" WHERE "name" LIKE '%_copy' ORDER BY "name"`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user01_copy", "user02_copy", "user03_copy"}, names)
+}
@@ -0,0 +1,95 @@
+package pgxscan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCopier struct {
+	table pgx.Identifier
+	cols  []string
+	rows  [][]interface{}
+}
+
+func (c *fakeCopier) CopyFrom(_ context.Context, table pgx.Identifier, cols []string, src pgx.CopyFromSource) (int64, error) {
+	c.table, c.cols = table, cols
+	for src.Next() {
+		values, err := src.Values()
+		if err != nil {
+			return 0, err
+		}
+		c.rows = append(c.rows, values)
+	}
+	return int64(len(c.rows)), src.Err()
+}
+
+type (
+	copyBase struct {
+		ID        int64 `db:"id,generated"`
+		CreatedBy string
+	}
+	copyUser struct {
+		copyBase
+		Name    string
+		Email   *string
+		Version int64         `db:"version,readonly"`
+		Address *namedAddress `db:"address,notate"`
+		Orders  []manyOrder   `db:"orders,many"`
+	}
+)
+
+func Test_CopyFromStructs(t *testing.T) {
+	c := &fakeCopier{}
+	users := []*copyUser{
+		{copyBase: copyBase{ID: 1, CreatedBy: "admin"}, Name: "user01", Email: stringToPtr("user01@email.com"), Version: 3},
+		nil,
+		{Name: "user02"},
+	}
+	n, err := CopyFromStructs(context.Background(), c, "public.users", users)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+	require.Equal(t, pgx.Identifier{"public", "users"}, c.table)
+	require.Equal(t, []string{"created_by", "name", "email"}, c.cols)
+	require.Equal(t, [][]interface{}{
+		{"admin", "user01", stringToPtr("user01@email.com")},
+		{nil, nil, nil},
+		{"", "user02", (*string)(nil)},
+	}, c.rows)
+
+	cols, values, err := InsertColumns(copyUser{})
+	require.NoError(t, err)
+	require.Equal(t, `"created_by", "name", "email"`, cols)
+	require.Equal(t, `:created_by, :name, :email`, values)
+	returning, err := ReturningColumns(copyUser{})
+	require.NoError(t, err)
+	require.Equal(t, `"id", "created_by", "name", "email", "version"`, returning)
+}
+
+type errSource struct {
+	sliceSource[genericUser]
+	err error
+}
+
+func (s *errSource) Err() error {
+	return s.err
+}
+
+func Test_CopyFromIter(t *testing.T) {
+	c := &fakeCopier{}
+	n, err := CopyFromIter[genericUser](context.Background(), c, "users", NewIter[genericUser](newGenericUserRows()))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+	require.Equal(t, []string{"id", "name"}, c.cols)
+	require.Equal(t, [][]interface{}{{int64(1), "user01"}, {int64(2), "user02"}}, c.rows)
+
+	src := &errSource{sliceSource: sliceSource[genericUser]{idx: -1}, err: errors.New("iteration failed")}
+	_, err = CopyFromIter[genericUser](context.Background(), &fakeCopier{}, "users", src)
+	require.EqualError(t, err, "iteration failed")
+
+	_, err = CopyFromStructs(context.Background(), c, "users", []int{1})
+	require.EqualError(t, err, "cannot list the columns of *int: expecting a struct")
+}
@@ -0,0 +1,55 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ScanError is returned when a column can not be scanned into the struct
+// field it is mapped to. It wraps the error returned by pgx, so errors.As and
+// errors.Is can be used to inspect the cause.
+type ScanError struct {
+	// Column is the name of the column, including its notation if any.
+	Column string
+	// Ordinal is the position of the column in the result set.
+	Ordinal int
+	// DataTypeOID is the postgres type OID of the column. It is 0 when the
+	// source does not describe its columns, like pgx.Row.
+	DataTypeOID uint32
+	// GoType is the type of the destination field.
+	GoType reflect.Type
+	// Field is the path of the destination field, like "Address.City".
+	Field string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("can't scan into dest[%d] (field '%s'): %v", e.Ordinal, e.Column, e.Err)
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// MissingFieldsError is returned when the result set has no column for a
+// field with the required tag option or, with MatchAllFields, for a field
+// without the optional tag option.
+type MissingFieldsError struct {
+	// Type is the struct type scanned into.
+	Type reflect.Type
+	// Fields lists the paths of the fields without column, like
+	// "Address.City", in declaration order.
+	Fields []string
+	// Columns lists the expected column of each field, like "address.city".
+	Columns []string
+}
+
+func (e *MissingFieldsError) Error() string {
+	missing := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		missing[i] = fmt.Sprintf(`%s (column "%s")`, field, e.Columns[i])
+	}
+	return fmt.Sprintf("missing columns for fields of %v: %s", e.Type, strings.Join(missing, ", "))
+}
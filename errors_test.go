@@ -0,0 +1,58 @@
+package pgxscan
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ScanError(t *testing.T) {
+	type (
+		Address struct {
+			ID   int64
+			City string
+		}
+		User struct {
+			ID      int64
+			Name    string
+			Address Address `scan:"notate"`
+		}
+	)
+	src := newFakeRows([]string{"id", "name", "notate:address", "id", "city"},
+		[]interface{}{int64(1), "user01", int64(0), int64(2), nil},
+	)
+
+	var user User
+	err := NewScanner(src).Scan(&user)
+	require.EqualError(t, err, "can't scan into dest[4] (field 'address.city'): cannot assign NULL to *string")
+
+	var scanErr *ScanError
+	require.True(t, errors.As(err, &scanErr))
+	require.Equal(t, "address.city", scanErr.Column)
+	require.Equal(t, 4, scanErr.Ordinal)
+	require.Equal(t, "Address.City", scanErr.Field)
+	require.Equal(t, reflect.TypeOf(""), scanErr.GoType)
+	require.Equal(t, uint32(0), scanErr.DataTypeOID)
+	require.EqualError(t, errors.Unwrap(err), "cannot assign NULL to *string")
+}
+
+func Test_ScanError_DataTypeOID(t *testing.T) {
+	type User struct {
+		ID   int64
+		Name int64
+	}
+	src := newFakeRows([]string{"id", "name"}, []interface{}{int64(1), "user01"})
+
+	var users []User
+	err := NewScanner(src).Scan(&users)
+
+	var scanErr *ScanError
+	require.True(t, errors.As(err, &scanErr))
+	require.Equal(t, "name", scanErr.Column)
+	require.Equal(t, 1, scanErr.Ordinal)
+	require.Equal(t, uint32(pgtype.TextOID), scanErr.DataTypeOID)
+	require.Equal(t, reflect.TypeOf(int64(0)), scanErr.GoType)
+}
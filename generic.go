@@ -0,0 +1,117 @@
+package pgxscan
+
+import (
+	"reflect"
+
+	"github.com/jackc/pgx/v4"
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+)
+
+// All scans every row of rows into a new slice of T and closes rows.
+// T can be a struct, a pointer to a struct or, for single column results,
+// a builtin type.
+//
+//	rows, _ := conn.Query(ctx, `SELECT * FROM users`)
+//	users, err := pgxscan.All[User](rows)
+func All[T any](src pgx.Rows, opts ...Option) ([]T, error) {
+	r := &rows{rows: src, cfg: newConfig(opts...)}
+	if err := checkDestType[T](r.cfg.Mapper); err != nil {
+		r.Close()
+		return nil, err
+	}
+	var dst []T
+	if err := r.Scan(&dst); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// One scans the single row of rows into a new T and closes rows.
+// T can be a struct, a pointer to a struct or a builtin type. Like ScanOne, it returns
+// pgx.ErrNoRows when there is no row and ErrTooManyRows when there is more
+// than one.
+//
+//	rows, _ := conn.Query(ctx, `SELECT * FROM users WHERE id = $1`, id)
+//	user, err := pgxscan.One[User](rows)
+func One[T any](src pgx.Rows, opts ...Option) (T, error) {
+	var dst T
+	r := &rows{rows: src, cfg: newConfig(opts...)}
+	if err := checkDestType[T](r.cfg.Mapper); err != nil {
+		r.Close()
+		return dst, err
+	}
+	if err := r.scanOne(&dst); err != nil {
+		var zero T
+		return zero, err
+	}
+	return dst, nil
+}
+
+// Iter is a typed iterator over rows. Unlike All it scans a single row at a
+// time, so large results never have to be held in memory.
+//
+//	it := pgxscan.NewIter[User](rows)
+//	defer it.Close()
+//	for it.Next() {
+//	    user := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	    return err
+//	}
+type Iter[T any] struct {
+	it  *Iterator
+	cur T
+}
+
+// NewIter returns an iterator scanning each row of rows into a T.
+// T can be a struct, a pointer to a struct or a builtin type.
+func NewIter[T any](src pgx.Rows, opts ...Option) *Iter[T] {
+	it := &Iter[T]{it: NewIterator(src, opts...)}
+	if err := checkDestType[T](it.it.rows.cfg.Mapper); err != nil {
+		it.it.fail(err)
+	}
+	return it
+}
+
+// Next scans the next row into the value returned by Value. It returns false
+// when there are no more rows or an error occurred.
+func (it *Iter[T]) Next() bool {
+	if !it.it.Next() {
+		return false
+	}
+	var cur T
+	if err := it.it.ScanRow(&cur); err != nil {
+		return false
+	}
+	it.cur = cur
+	return true
+}
+
+// Value returns the row scanned by the last call to Next.
+func (it *Iter[T]) Value() T {
+	return it.cur
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (it *Iter[T]) Err() error {
+	return it.it.Err()
+}
+
+// Close closes the underlying rows. It is safe to call Close more than once.
+func (it *Iter[T]) Close() {
+	it.it.Close()
+}
+
+// checkDestType reports whether T, or what T points to, can be mapped by m
+// when it is a struct.
+func checkDestType[T any](m *Mapper) error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if !sqlmaper.IsStruct(t.Kind()) || sqlmaper.ImplementsScanner(t) {
+		return nil
+	}
+	_, err := m.columnMap(t)
+	return err
+}
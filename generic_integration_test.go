@@ -0,0 +1,54 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	ID    uint32
+	Name  string
+	Email string
+}
+
+func Test_All(t *testing.T) {
+	rows, err := newTestDB(t).Query(context.Background(), `SELECT "id", "name", "email" FROM "users" WHERE "id" < $1 ORDER BY "id"`, 3)
+	require.NoError(t, err)
+
+	users, err := pgxscan.All[user](rows)
+	require.NoError(t, err)
+	require.Equal(t, []user{
+		{ID: 1, Name: "user01", Email: "user01@email.com"},
+		{ID: 2, Name: "user02", Email: "user02@email.com"},
+	}, users)
+}
+
+func Test_One(t *testing.T) {
+	rows, err := newTestDB(t).Query(context.Background(), `SELECT "id", "name", "email" FROM "users" WHERE "id" = $1`, 1)
+	require.NoError(t, err)
+
+	u, err := pgxscan.One[*user](rows)
+	require.NoError(t, err)
+	require.Equal(t, &user{ID: 1, Name: "user01", Email: "user01@email.com"}, u)
+}
+
+func Test_Iter(t *testing.T) {
+	rows, err := newTestDB(t).Query(context.Background(), `SELECT "id", "name", "email" FROM "users" WHERE "id" < $1 ORDER BY "id"`, 3)
+	require.NoError(t, err)
+
+	it := pgxscan.NewIter[user](rows)
+	defer it.Close()
+
+	var ids []uint32
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []uint32{1, 2}, ids)
+}
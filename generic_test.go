@@ -0,0 +1,84 @@
+package pgxscan
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type genericUser struct {
+	ID   int64
+	Name string
+}
+
+func newGenericUserRows() *fakeRows {
+	return newFakeRows([]string{"id", "name"},
+		[]interface{}{int64(1), "user01"},
+		[]interface{}{int64(2), "user02"},
+	)
+}
+
+func Test_All(t *testing.T) {
+	users, err := All[genericUser](newGenericUserRows())
+	require.NoError(t, err)
+	require.Equal(t, []genericUser{{ID: 1, Name: "user01"}, {ID: 2, Name: "user02"}}, users)
+
+	ptrs, err := All[*genericUser](newGenericUserRows())
+	require.NoError(t, err)
+	require.Equal(t, []*genericUser{{ID: 1, Name: "user01"}, {ID: 2, Name: "user02"}}, ptrs)
+}
+
+func Test_All_Scalars(t *testing.T) {
+	ids, err := All[int64](newFakeRows([]string{"id"}, []interface{}{int64(1)}, []interface{}{int64(2)}))
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, ids)
+
+	src := newGenericUserRows()
+	_, err = All[int64](src)
+	require.EqualError(t, err, "scanning into []int64 requires a single column, got 2")
+	require.True(t, src.closed)
+}
+
+func Test_One(t *testing.T) {
+	src := newFakeRows([]string{"id", "name"}, []interface{}{int64(1), "user01"})
+	user, err := One[genericUser](src)
+	require.NoError(t, err)
+	require.Equal(t, genericUser{ID: 1, Name: "user01"}, user)
+
+	src = newFakeRows([]string{"id", "name"}, []interface{}{int64(1), "user01"})
+	ptr, err := One[*genericUser](src)
+	require.NoError(t, err)
+	require.Equal(t, &genericUser{ID: 1, Name: "user01"}, ptr)
+
+	_, err = One[genericUser](newFakeRows([]string{"id", "name"}))
+	require.Equal(t, pgx.ErrNoRows, err)
+}
+
+func Test_Iter(t *testing.T) {
+	src := newGenericUserRows()
+	it := NewIter[genericUser](src)
+	defer it.Close()
+
+	var users []genericUser
+	for it.Next() {
+		users = append(users, it.Value())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []genericUser{{ID: 1, Name: "user01"}, {ID: 2, Name: "user02"}}, users)
+}
+
+func Test_Iter_WantErr_UnmappedColumn(t *testing.T) {
+	src := newFakeRows([]string{"id", "name", "email"},
+		[]interface{}{int64(1), "user01", "user01@email.com"},
+	)
+	it := NewIter[genericUser](src)
+	require.False(t, it.Next())
+	require.EqualError(t, it.Err(), `unable to find corresponding field to column "email" returned by query`)
+	require.True(t, src.closed)
+}
+
+func Test_One_WantErr_TooManyRows(t *testing.T) {
+	_, err := One[genericUser](newGenericUserRows())
+	require.Equal(t, ErrTooManyRows, err)
+}
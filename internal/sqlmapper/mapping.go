@@ -13,14 +13,49 @@ type (
 		ColumnName string
 		FieldIndex []int
 		GoType     reflect.Type
+		// PrimaryKey is set for the fields with the pk option, which identify
+		// the rows of a struct type.
+		PrimaryKey bool
+		// Many is set for the slice of structs fields with the many option,
+		// which are filled from the rows sharing the same primary key.
+		Many bool
+		// ReadOnly is set for the fields with the readonly or generated
+		// option, whose columns are scanned but never written.
+		ReadOnly bool
+		// Inline is set for the map[string]interface{} fields with the inline
+		// option, which collect the columns that are not mapped to a field.
+		Inline bool
+		// Required is set for the fields with the required option, which
+		// fail the scan when the result set has no column for them.
+		Required bool
+		// Optional is set for the fields with the optional option, or in a
+		// nested struct with it, which are exempt from the fields check.
+		Optional bool
+		// JSON is set for the fields with the json option, whose column is
+		// decoded from json with the mapping rules of their type, even when
+		// it is a struct or a slice of structs.
+		JSON bool
 	}
 	ColumnMap map[string]ColumnData
+	// TableMap holds the column prefix of the nested structs with the table
+	// option by table name, like "address" for `db:"address,table=addresses"`.
+	// The name is schema qualified when the option is, like "sales.addresses".
+	TableMap map[string]string
 )
 
 const (
-	followTagName = "follow"
-	embedTagName  = "embed"
-	notateTagName = "notate"
+	followTagName    = "follow"
+	embedTagName     = "embed"
+	notateTagName    = "notate"
+	pkTagName        = "pk"
+	manyTagName      = "many"
+	tableTagName     = "table"
+	readOnlyTagName  = "readonly"
+	generatedTagName = "generated"
+	inlineTagName    = "inline"
+	requiredTagName  = "required"
+	optionalTagName  = "optional"
+	jsonTagName      = "json"
 )
 
 func IsEmptyValue(v reflect.Value) bool {
@@ -43,42 +78,138 @@ func IsEmptyValue(v reflect.Value) bool {
 	return false
 }
 
-var structMapCache = make(map[interface{}]ColumnMap)
-var structMapCacheLock = sync.Mutex{}
-
-// notateByDefault will dot annotate an embedded struct
-//
-// Example:
-//
-//	type EmbeddedStruct struct {
-//	   String string
-//	}
-//
-//	type Struct struct {
-//	    TableOne EmbeddedStruct `db:"table_one"`
-//	}
-//
-// Output: "table_one"."string"
-//
-// This is true by default
-// This can be changed by calling AnnotatedByDefault(bool)
-var notateByDefault = false
+// MapperOptions holds the conventions a Mapper uses to map struct fields to
+// column names.
+type MapperOptions struct {
+	// TagName is the struct tag holding the column name and its options.
+	// Defaults to "db".
+	TagName string
 
-func NotatedByDefault(notate bool) {
-	notateByDefault = notate
+	// ColumnRename returns the column name of a field without a named tag.
+	// Defaults to converting the field name to snake case.
+	ColumnRename func(string) string
+
+	// NotateByDefault will dot annotate an embedded struct
+	//
+	// Example:
+	//
+	//	type EmbeddedStruct struct {
+	//	   String string
+	//	}
+	//
+	//	type Struct struct {
+	//	    TableOne EmbeddedStruct `db:"table_one"`
+	//	}
+	//
+	// Output: "table_one"."string"
+	NotateByDefault bool
+
+	// MaxDepth is the maximum number of nested struct levels mapped below a
+	// struct. Going past it is an error, except for the fields of a
+	// self-referential struct, which are mapped MaxDepth levels deep, like
+	// "manager.manager.name" for a MaxDepth of 2. When MaxDepth is 0,
+	// DefaultMaxDepth is used and self-referential structs are an error.
+	MaxDepth int
+
+	// RejectAmbiguous makes GetColumnMap return an error when two fields
+	// at the same depth, like the fields of two embedded structs, map the
+	// same column. Otherwise, like in Go, the shallowest field is mapped and
+	// the first one declared wins among fields at the same depth.
+	RejectAmbiguous bool
+}
+
+// DefaultMaxDepth is the maximum number of nested struct levels mapped when
+// MapperOptions.MaxDepth is not set.
+const DefaultMaxDepth = 16
+
+// Mapper maps struct types to their ColumnMap. Column maps are computed once
+// per type and cached on the Mapper, so two mappers with different options
+// never share mappings.
+type Mapper struct {
+	opts   MapperOptions
+	cache  map[reflect.Type]ColumnMap
+	tables map[reflect.Type]TableMap
+	lock   sync.Mutex
+	// onUpdate is called when the options change, see OnUpdate.
+	onUpdate func()
 }
-func isNotated() bool {
-	return notateByDefault
+
+// NewMapper returns a Mapper using opts, zero values are replaced by the defaults.
+func NewMapper(opts MapperOptions) *Mapper {
+	if opts.TagName == "" {
+		opts.TagName = defaultTagName
+	}
+	if opts.ColumnRename == nil {
+		opts.ColumnRename = defaultColumnRenameFunction
+	}
+	return &Mapper{
+		opts:   opts,
+		cache:  make(map[reflect.Type]ColumnMap),
+		tables: make(map[reflect.Type]TableMap),
+	}
+}
+
+// Options returns the options of m.
+func (m *Mapper) Options() MapperOptions {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.opts
 }
 
+// OnUpdate sets fn to be called after the options of m change, so the caches
+// built from its mappings can be dropped with them.
+func (m *Mapper) OnUpdate(fn func()) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.onUpdate = fn
+}
+
+// update changes the options of m and drops the mappings computed with the
+// previous ones.
+func (m *Mapper) update(fn func(*MapperOptions)) {
+	m.lock.Lock()
+	fn(&m.opts)
+	m.cache = make(map[reflect.Type]ColumnMap)
+	m.tables = make(map[reflect.Type]TableMap)
+	onUpdate := m.onUpdate
+	m.lock.Unlock()
+	if onUpdate != nil {
+		onUpdate()
+	}
+}
+
+const defaultTagName = "db"
+
 var camelCaseColumnRenameFunction = camelCase
 var lowerCaseColumnRenameFunction = strings.ToLower
 
 var defaultColumnRenameFunction = camelCaseColumnRenameFunction
-var columnRenameFunction = defaultColumnRenameFunction
 
+// SnakeCase converts a field name like "HTTPServer" to "http_server". It is
+// the default column rename function.
+func SnakeCase(str string) string {
+	return camelCase(str)
+}
+
+var defaultMapper = NewMapper(MapperOptions{})
+
+// DefaultMapper returns the Mapper used by the package level functions.
+func DefaultMapper() *Mapper {
+	return defaultMapper
+}
+
+// NotatedByDefault sets MapperOptions.NotateByDefault on the default mapper.
+func NotatedByDefault(notate bool) {
+	defaultMapper.update(func(opts *MapperOptions) {
+		opts.NotateByDefault = notate
+	})
+}
+
+// SetColumnRenameFunction sets MapperOptions.ColumnRename on the default mapper.
 func SetColumnRenameFunction(newFunction func(string) string) {
-	columnRenameFunction = newFunction
+	defaultMapper.update(func(opts *MapperOptions) {
+		opts.ColumnRename = newFunction
+	})
 }
 
 // GetSliceElementType returns the type for a slices elements.
@@ -160,6 +291,22 @@ func SafeSetFieldByIndex(v reflect.Value, fieldIndex []int, src interface{}) (re
 	return v
 }
 
+// FieldByIndex returns the nested field of v corresponding to fieldIndex.
+// Unlike reflect.Value.FieldByIndex it allocates the nil pointers to structs
+// it goes through, so the returned field can always be set.
+func FieldByIndex(v reflect.Value, fieldIndex []int) reflect.Value {
+	for i, x := range fieldIndex {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
 type rowData = map[string]interface{}
 
 // AssignStructVals will assign the data from rd to i.
@@ -174,88 +321,281 @@ func AssignStructVals(i interface{}, rd rowData, cm ColumnMap) {
 	}
 }
 
+// FieldOptions returns the options of the tag of f merged with the values
+// of its "scan" tag, for more manageable tag handling.
+func (m *Mapper) FieldOptions(f reflect.StructField) Options {
+	dbTag := NewTag(m.opts.TagName, f.Tag)
+	scanTag := NewTag("scan", f.Tag).Values()
+	return append(dbTag.Options(), scanTag...)
+}
+
+// GetColumnMap returns the column map of i using the default mapper.
 func GetColumnMap(i interface{}) (ColumnMap, error) {
+	return defaultMapper.GetColumnMap(i)
+}
+
+// GetColumnMap returns the column map of i, which is a struct, a slice of
+// structs or a pointer to one of those.
+func (m *Mapper) GetColumnMap(i interface{}) (ColumnMap, error) {
 	val := reflect.Indirect(reflect.ValueOf(i))
 	t, valKind := GetTypeInfo(i, val)
 	if valKind != reflect.Struct {
 		return nil, fmt.Errorf("cannot scan into this type: %v", t) // #nosec
 	}
 
-	structMapCacheLock.Lock()
-	defer structMapCacheLock.Unlock()
-	if _, ok := structMapCache[t]; !ok {
-		structMapCache[t] = createColumnMap(t, []int{}, []string{})
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.load(t); err != nil {
+		return nil, err
+	}
+	return m.cache[t], nil
+}
+
+// GetTableMap returns the table map of i, which is a struct, a slice of
+// structs or a pointer to one of those.
+func (m *Mapper) GetTableMap(i interface{}) (TableMap, error) {
+	val := reflect.Indirect(reflect.ValueOf(i))
+	t, valKind := GetTypeInfo(i, val)
+	if valKind != reflect.Struct {
+		return nil, fmt.Errorf("cannot scan into this type: %v", t) // #nosec
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.load(t); err != nil {
+		return nil, err
+	}
+	return m.tables[t], nil
+}
+
+// load computes the mappings of t unless they are cached, m.lock must be held.
+func (m *Mapper) load(t reflect.Type) error {
+	if _, ok := m.cache[t]; !ok {
+		tables := TableMap{}
+		conflicts := map[string]conflict{}
+		cm, err := m.createColumnMap(t, []int{}, []string{}, tables, conflicts, structPath{types: []reflect.Type{t}})
+		if err != nil {
+			return err
+		}
+		if m.opts.RejectAmbiguous {
+			if err := checkAmbiguous(t, cm, conflicts); err != nil {
+				return err
+			}
+		}
+		m.cache[t] = cm
+		m.tables[t] = tables
+	}
+	return nil
+}
+
+// structPath holds the struct types createColumnMap is recursing into, the
+// mapped struct first, and the names of the fields leading to them.
+type structPath struct {
+	types  []reflect.Type
+	fields []string
+}
+
+// enter returns the path to the nested struct field f of type t. It reports
+// false when the field is skipped, because it is past the max depth of a
+// self-referential struct.
+func (m *Mapper) enter(path structPath, f reflect.StructField, t reflect.Type) (structPath, bool, error) {
+	next := structPath{
+		types:  append(append([]reflect.Type{}, path.types...), t),
+		fields: append(append([]string{}, path.fields...), f.Name),
+	}
+	cyclic := false
+	for _, typ := range path.types {
+		if typ == t {
+			cyclic = true
+			break
+		}
+	}
+	maxDepth := m.opts.MaxDepth
+	if maxDepth <= 0 {
+		if cyclic {
+			return next, false, fmt.Errorf("field %s of %v refers back to %v, set a max depth to map self-referential structs", strings.Join(next.fields, "."), path.types[0], t)
+		}
+		maxDepth = DefaultMaxDepth
+	}
+	if len(next.fields) > maxDepth {
+		if cyclic {
+			return next, false, nil
+		}
+		return next, false, fmt.Errorf("field %s of %v is nested deeper than the max depth of %d", strings.Join(next.fields, "."), path.types[0], maxDepth)
+	}
+	return next, true, nil
+}
+
+// conflict holds two fields at the same depth mapping the same column.
+type conflict struct {
+	first, second []int
+}
+
+// checkAmbiguous returns an error naming the fields of the first column of cm,
+// in column order, mapped by two fields at the depth of its mapped field.
+func checkAmbiguous(t reflect.Type, cm ColumnMap, conflicts map[string]conflict) error {
+	for _, col := range cm.Cols() {
+		c, ok := conflicts[col]
+		if ok && len(c.first) == len(cm[col].FieldIndex) {
+			return fmt.Errorf(`column "%s" of %v is ambiguous, it is mapped by fields %s and %s`, col, t, fieldPath(t, c.first), fieldPath(t, c.second))
+		}
+	}
+	return nil
+}
+
+// fieldPath returns the dotted names of the fields of t along fieldIndex.
+func fieldPath(t reflect.Type, fieldIndex []int) string {
+	names := make([]string, len(fieldIndex))
+	for i, x := range fieldIndex {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		f := t.Field(x)
+		names[i] = f.Name
+		t = f.Type
+	}
+	return strings.Join(names, ".")
+}
+
+// mergeColumn adds data to cm under key unless a shallower field, or one at
+// the same depth that was declared first, already maps it. Fields at the
+// same depth are recorded in conflicts.
+func mergeColumn(cm ColumnMap, key string, data ColumnData, conflicts map[string]conflict) {
+	prev, ok := cm[key]
+	switch {
+	case !ok || len(data.FieldIndex) < len(prev.FieldIndex):
+		cm[key] = data
+	case len(data.FieldIndex) == len(prev.FieldIndex):
+		if c, ok := conflicts[key]; !ok || len(c.first) > len(data.FieldIndex) {
+			conflicts[key] = conflict{first: prev.FieldIndex, second: data.FieldIndex}
+		}
 	}
-	return structMapCache[t], nil
 }
 
-func createColumnMap(t reflect.Type, fieldIndex []int, prefixes []string) ColumnMap {
+func (m *Mapper) createColumnMap(t reflect.Type, fieldIndex []int, prefixes []string, tables TableMap, conflicts map[string]conflict, path structPath) (ColumnMap, error) {
 	cm, n := ColumnMap{}, t.NumField()
 	var subColMaps []ColumnMap
 	for i := 0; i < n; i++ {
 		f := t.Field(i)
-		dbTag := NewTag("db", f.Tag)
+		dbTag := NewTag(m.opts.TagName, f.Tag)
 		if !dbTag.Ignore() {
-			// get scan tags if present
-			scanTag := NewTag("scan", f.Tag).Values()
-			// merge dbTag options with scan tags for more manageable tag handling
-			options := append(dbTag.Options(), scanTag...)
+			options := m.FieldOptions(f)
 			var columnName string
 
 			if !dbTag.IsNamed() {
-				columnName = columnRenameFunction(f.Name)
+				columnName = m.opts.ColumnRename(f.Name)
 			} else {
 				columnName = dbTag.Name()
 			}
 
-			if (f.Anonymous || options.Contains(followTagName)) && IsUnderlyingStruct(f.Type) {
-				subFieldIndexes := append(fieldIndex, f.Index...)
+			// the column of a json field is a single value, whatever its type
+			isJSON := options.Contains(jsonTagName)
+
+			if !isJSON && (f.Anonymous || options.Contains(followTagName)) && IsUnderlyingStruct(f.Type) {
+				subFieldIndexes := appendIndex(fieldIndex, f.Index)
 
 				if f.Type.Kind() == reflect.Ptr {
 					f.Type = f.Type.Elem()
 				}
+				subPath, ok, err := m.enter(path, f, f.Type)
+				if err != nil {
+					return nil, err
+				} else if !ok {
+					continue
+				}
 
+				subPrefixes := prefixes
 				if dbTag.IsNamed() && !options.Contains(followTagName) {
-					subPrefixes := append(prefixes, columnName)
-					subColMaps = append(subColMaps, createColumnMap(f.Type, subFieldIndexes, subPrefixes))
-				} else {
-					subColMaps = append(subColMaps, createColumnMap(f.Type, subFieldIndexes, prefixes))
+					subPrefixes = appendPrefix(prefixes, columnName)
 				}
+				subCm, err := m.createColumnMap(f.Type, subFieldIndexes, subPrefixes, tables, conflicts, subPath)
+				if err != nil {
+					return nil, err
+				}
+				subColMaps = append(subColMaps, subCm.optional(options.Contains(optionalTagName)))
 
-			} else if !implementsScanner(f.Type) && (isNotated() || options.Contains(notateTagName)) && !options.Contains(embedTagName) {
-				subFieldIndexes := append(fieldIndex, f.Index...)
-				subPrefixes := append(prefixes, columnName)
-				var subCm ColumnMap
-				if f.Type.Kind() == reflect.Ptr {
-					subCm = createColumnMap(f.Type.Elem(), subFieldIndexes, subPrefixes)
-				} else {
-					subCm = createColumnMap(f.Type, subFieldIndexes, subPrefixes)
+			} else if !isJSON && !ImplementsScanner(f.Type) && (m.opts.NotateByDefault || options.Contains(notateTagName) || hasTable(options)) && !options.Contains(embedTagName) {
+				subFieldIndexes := appendIndex(fieldIndex, f.Index)
+				subPrefixes := appendPrefix(prefixes, columnName)
+				subType := f.Type
+				if subType.Kind() == reflect.Ptr {
+					subType = subType.Elem()
+				}
+				subPath, ok, err := m.enter(path, f, subType)
+				if err != nil {
+					return nil, err
+				} else if !ok {
+					continue
+				}
+				if table, ok := options.Value(tableTagName); ok && IsUnderlyingStruct(f.Type) {
+					if _, ok := tables[table]; !ok {
+						tables[table] = strings.Join(subPrefixes, ".")
+					}
+				}
+				subCm, err := m.createColumnMap(subType, subFieldIndexes, subPrefixes, tables, conflicts, subPath)
+				if err != nil {
+					return nil, err
 				}
 				if len(subCm) != 0 {
-					subColMaps = append(subColMaps, subCm)
+					subColMaps = append(subColMaps, subCm.optional(options.Contains(optionalTagName)))
 					continue
 				}
 			} else if f.PkgPath == "" {
 				// if PkgPath is empty then it is an exported field
-				columnName = strings.Join(append(prefixes, columnName), ".")
-				cm[columnName] = ColumnData{
+				columnName = strings.Join(appendPrefix(prefixes, columnName), ".")
+				mergeColumn(cm, columnName, ColumnData{
 					ColumnName: columnName,
-					FieldIndex: append(fieldIndex, f.Index...),
+					FieldIndex: appendIndex(fieldIndex, f.Index),
 					GoType:     f.Type,
-				}
+					PrimaryKey: options.Contains(pkTagName),
+					Many:       !isJSON && options.Contains(manyTagName) && IsSlice(f.Type.Kind()) && IsUnderlyingStruct(f.Type.Elem()),
+					ReadOnly:   options.Contains(readOnlyTagName) || options.Contains(generatedTagName),
+					Inline:     options.Contains(inlineTagName) && f.Type.Kind() == reflect.Map && f.Type.Key().Kind() == reflect.String && f.Type.Elem().Kind() == reflect.Interface,
+					Required:   options.Contains(requiredTagName),
+					Optional:   options.Contains(optionalTagName),
+					JSON:       isJSON,
+				}, conflicts)
 			}
 		}
 	}
 	for _, subCm := range subColMaps {
 		for key, val := range subCm {
-			if _, ok := cm[key]; !ok {
-				cm[key] = val
-			}
+			mergeColumn(cm, key, val, conflicts)
+		}
+	}
+	return cm, nil
+}
+
+// appendIndex returns a new field index made of index and sub, which never
+// shares its backing array with index.
+func appendIndex(index, sub []int) []int {
+	return append(append(make([]int, 0, len(index)+len(sub)), index...), sub...)
+}
+
+// appendPrefix returns new prefixes made of prefixes and name.
+func appendPrefix(prefixes []string, name string) []string {
+	return append(append(make([]string, 0, len(prefixes)+1), prefixes...), name)
+}
+
+// optional marks the columns of cm as optional when b is set, for the
+// columns of a nested struct field with the optional option.
+func (cm ColumnMap) optional(b bool) ColumnMap {
+	if b {
+		for key, data := range cm {
+			data.Optional = true
+			cm[key] = data
 		}
 	}
 	return cm
 }
 
+// hasTable reports whether options route the columns of a table to the field,
+// which notates it.
+func hasTable(options Options) bool {
+	_, ok := options.Value(tableTagName)
+	return ok
+}
+
 func IsUnderlyingStruct(t reflect.Type) bool {
 	return t.Kind() == reflect.Struct || (t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct)
 }
@@ -268,7 +608,9 @@ func (cm ColumnMap) Cols() []string {
 	return structCols
 }
 
-func implementsScanner(t reflect.Type) bool {
+// ImplementsScanner reports whether t, or what t points to, is scanned as a
+// single value, either because it implements sql.Scanner or because it is a builtin.
+func ImplementsScanner(t reflect.Type) bool {
 	if IsPointer(t.Kind()) {
 		t = t.Elem()
 	}
@@ -73,6 +73,44 @@ func (rt *reflectTest) TestColumnRename() {
 	SetColumnRenameFunction(defaultColumnRenameFunction)
 }
 
+func (rt *reflectTest) TestColumnRenameDropsCachedMappings() {
+	type TestStruct struct {
+		FirstName string
+	}
+	cm, err := GetColumnMap(&TestStruct{})
+	rt.NoError(err)
+	rt.Equal([]string{"first_name"}, cm.Cols())
+
+	SetColumnRenameFunction(strings.ToUpper)
+	defer SetColumnRenameFunction(defaultColumnRenameFunction)
+
+	cm, err = GetColumnMap(&TestStruct{})
+	rt.NoError(err)
+	rt.Equal([]string{"FIRSTNAME"}, cm.Cols())
+}
+
+func (rt *reflectTest) TestNewMapper() {
+	type TestStruct struct {
+		FirstName string
+		LastName  string `sql:"surname"`
+		Ignored   string `db:"ignored"`
+	}
+	m := NewMapper(MapperOptions{TagName: "sql", ColumnRename: strings.ToLower})
+	cm, err := m.GetColumnMap(&TestStruct{})
+	rt.NoError(err)
+	rt.Equal([]string{"firstname", "ignored", "surname"}, cm.Cols())
+
+	// the default mapper is not affected
+	cm, err = GetColumnMap(&TestStruct{})
+	rt.NoError(err)
+	rt.Equal([]string{"first_name", "ignored", "last_name"}, cm.Cols())
+
+	defaults := NewMapper(MapperOptions{}).Options()
+	rt.Equal("db", defaults.TagName)
+	rt.False(defaults.NotateByDefault)
+	rt.Equal("http_server", defaults.ColumnRename("HTTPServer"))
+}
+
 func (rt *reflectTest) TestParallelGetColumnMap() {
 
 	type item struct {
@@ -817,6 +855,30 @@ func (rt *reflectTest) TestGetColumnMap_withStruct() {
 		"valuer": {ColumnName: "valuer", FieldIndex: []int{3}, GoType: reflect.TypeOf(&sql.NullString{})},
 	}, cm)
 }
+func (rt *reflectTest) TestGetTableMap() {
+	type (
+		Geo struct {
+			Lat float64
+		}
+		Address struct {
+			City string
+			Geo  *Geo `db:"geo,table=geos"`
+		}
+		TestStruct struct {
+			ID      int64
+			Address Address `db:"address,table=addresses"`
+		}
+	)
+	var ts TestStruct
+	tables, err := DefaultMapper().GetTableMap(&ts)
+	rt.NoError(err)
+	rt.Equal(TableMap{"addresses": "address", "geos": "address.geo"}, tables)
+
+	cm, err := GetColumnMap(&ts)
+	rt.NoError(err)
+	rt.Equal([]string{"address.city", "address.geo.lat", "id"}, cm.Cols())
+}
+
 func (rt *reflectTest) TestGetColumnMap_withStructWithTag() {
 
 	type TestStruct struct {
@@ -1367,3 +1429,89 @@ func (rt *reflectTest) TestAppendSliceElement() {
 func TestReflectSuite(t *testing.T) {
 	suite.Run(t, new(reflectTest))
 }
+
+type cyclicEmployee struct {
+	ID      int64
+	Name    string
+	Manager *cyclicEmployee `db:"manager,notate"`
+}
+
+type cyclicNode struct {
+	*cyclicNode
+	Value string
+}
+
+func (rt *reflectTest) TestGetColumnMap_cyclicStruct() {
+	_, err := NewMapper(MapperOptions{}).GetColumnMap(&cyclicEmployee{})
+	rt.EqualError(err, "field Manager of sqlmaper.cyclicEmployee refers back to sqlmaper.cyclicEmployee, set a max depth to map self-referential structs")
+
+	_, err = NewMapper(MapperOptions{}).GetColumnMap(&cyclicNode{})
+	rt.EqualError(err, "field cyclicNode of sqlmaper.cyclicNode refers back to sqlmaper.cyclicNode, set a max depth to map self-referential structs")
+
+	cm, err := NewMapper(MapperOptions{MaxDepth: 2}).GetColumnMap(&cyclicEmployee{})
+	rt.NoError(err)
+	rt.Equal([]string{"id", "manager.id", "manager.manager.id", "manager.manager.name", "manager.name", "name"}, cm.Cols())
+	rt.Equal([]int{2, 2, 1}, cm["manager.manager.name"].FieldIndex)
+	rt.Equal([]int{2, 1}, cm["manager.name"].FieldIndex)
+}
+
+func (rt *reflectTest) TestGetColumnMap_maxDepth() {
+	type (
+		Level3 struct {
+			Value string
+		}
+		Level2 struct {
+			Level3 Level3 `db:"level3,notate"`
+		}
+		Level1 struct {
+			Level2 Level2 `db:"level2,notate"`
+		}
+		TestStruct struct {
+			Level1 Level1 `db:"level1,notate"`
+		}
+	)
+	_, err := NewMapper(MapperOptions{MaxDepth: 2}).GetColumnMap(&TestStruct{})
+	rt.EqualError(err, "field Level1.Level2.Level3 of sqlmaper.TestStruct is nested deeper than the max depth of 2")
+
+	cm, err := NewMapper(MapperOptions{MaxDepth: 3}).GetColumnMap(&TestStruct{})
+	rt.NoError(err)
+	rt.Equal([]string{"level1.level2.level3.value"}, cm.Cols())
+}
+
+type (
+	ambiguousUser struct {
+		ID   int64
+		Name string
+	}
+	ambiguousOrder struct {
+		ID    int64
+		Total int64
+	}
+	ambiguousRow struct {
+		ambiguousUser
+		ambiguousOrder
+	}
+	shadowedRow struct {
+		ID    int64          `db:"id"`
+		Order ambiguousOrder `db:"order,follow"`
+		ambiguousRow
+	}
+)
+
+func (rt *reflectTest) TestGetColumnMap_ambiguousColumns() {
+	// like Go, the first embedded field declared is mapped by default
+	cm, err := NewMapper(MapperOptions{}).GetColumnMap(&ambiguousRow{})
+	rt.NoError(err)
+	rt.Equal([]string{"id", "name", "total"}, cm.Cols())
+	rt.Equal([]int{0, 0}, cm["id"].FieldIndex)
+
+	_, err = NewMapper(MapperOptions{RejectAmbiguous: true}).GetColumnMap(&ambiguousRow{})
+	rt.EqualError(err, `column "id" of sqlmaper.ambiguousRow is ambiguous, it is mapped by fields ambiguousUser.ID and ambiguousOrder.ID`)
+
+	// a shallower field wins, even when it is declared last
+	cm, err = NewMapper(MapperOptions{RejectAmbiguous: true}).GetColumnMap(&shadowedRow{})
+	rt.NoError(err)
+	rt.Equal([]int{0}, cm["id"].FieldIndex)
+	rt.Equal([]int{1, 1}, cm["total"].FieldIndex)
+	rt.Equal([]int{2, 0, 1}, cm["name"].FieldIndex)
+}
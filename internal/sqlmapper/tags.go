@@ -89,3 +89,13 @@ func (o Options) Contains(optionName string) bool {
 func (o Options) IsEmpty() bool {
 	return len(o) == 0
 }
+
+// Value returns the value of a "name=value" option.
+func (o Options) Value(optionName string) (string, bool) {
+	for _, s := range o {
+		if name, value, ok := strings.Cut(s, "="); ok && name == optionName {
+			return value, true
+		}
+	}
+	return "", false
+}
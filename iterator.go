@@ -0,0 +1,121 @@
+package pgxscan
+
+import (
+	"reflect"
+
+	"github.com/jackc/pgx/v4"
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+)
+
+// Iterator scans a result set one row at a time instead of consuming and
+// closing it like the Scanner returned by NewScanner does. The column names
+// and the scan plan of the destination are computed once and reused for
+// every row, so millions of rows can be streamed into structs without
+// building a slice.
+//
+//	it := pgxscan.NewIterator(rows)
+//	defer it.Close()
+//	for it.Next() {
+//	    var user User
+//	    if err := it.ScanRow(&user); err != nil {
+//	        return err
+//	    }
+//	}
+//	if err := it.Err(); err != nil {
+//	    return err
+//	}
+type Iterator struct {
+	rows   *rows
+	plan   *scanPlan
+	record *recordPlan
+	err    error
+}
+
+// NewIterator returns an Iterator over src.
+func NewIterator(src pgx.Rows, opts ...Option) *Iterator {
+	return &Iterator{rows: &rows{rows: src, cfg: newConfig(opts...)}}
+}
+
+// Next prepares the next row for ScanRow. It returns false when there are no
+// more rows or an error occurred, in which case the rows are closed.
+func (it *Iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	return it.rows.Next()
+}
+
+// ScanRow scans the current row into dst. dst is either a pointer to a
+// struct, a pointer to a map[string]interface{} or, like Scan, a list of
+// pointers to builtin types. A failed ScanRow closes
+// the rows and the error is also reported by Err.
+//
+// Rows are not aggregated, the many fields of dst only get the element of
+// the current row.
+func (it *Iterator) ScanRow(dst ...interface{}) error {
+	if it.err != nil {
+		return it.err
+	}
+	if err := it.scanRow(dst...); err != nil {
+		it.fail(err)
+		return err
+	}
+	return nil
+}
+
+func (it *Iterator) scanRow(dst ...interface{}) error {
+	if dst == nil {
+		return nil
+	} else if isVariadic(dst...) {
+		return it.rows.rows.Scan(dst...)
+	} else if ii, ok := dst[0].([]interface{}); ok {
+		return it.rows.rows.Scan(ii...)
+	} else if isRecordDest(dst[0]) {
+		if it.record == nil {
+			record, err := it.rows.recordPlan()
+			if err != nil {
+				return err
+			}
+			it.record = record
+		}
+		return it.rows.scanRecordInto(it.record, dst[0])
+	}
+
+	val, err := validate(dst[0])
+	if err != nil {
+		return err
+	}
+	if val.Kind() != reflect.Struct || sqlmaper.ImplementsScanner(val.Type()) {
+		return it.rows.rows.Scan(dst...)
+	}
+	if it.plan == nil || it.plan.typ != val.Type() {
+		cols, err := it.rows.structColumns(val.Type())
+		if err != nil {
+			return err
+		}
+		plan, err := it.rows.cfg.Mapper.scanPlan(val.Type(), cols, it.rows.cfg.MatchAllColumnsToStruct, it.rows.cfg.MatchAllFields)
+		if err != nil {
+			return err
+		}
+		it.plan = plan
+	}
+	return it.rows.scanStruct(it.plan, val)
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (it *Iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.rows.Err()
+}
+
+// Close closes the underlying rows. It is safe to call Close more than once.
+func (it *Iterator) Close() {
+	it.rows.Close()
+}
+
+func (it *Iterator) fail(err error) {
+	it.err = err
+	it.rows.Close()
+}
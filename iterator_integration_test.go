@@ -0,0 +1,34 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/randallmlough/pgxscan"
+	"github.com/randallmlough/pgxscan/testdata"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Iterator(t *testing.T) {
+	stmt := `SELECT "id", "int", "float_32", "string", "time", "bool", "bytes", "string_slice", "json_b" FROM "test" ORDER BY "id" ASC LIMIT 2`
+	rows, err := newTestDB(t).Query(context.Background(), stmt)
+	require.NoError(t, err)
+
+	it := pgxscan.NewIterator(rows)
+	defer it.Close()
+
+	var dst []testdata.TestStruct
+	for it.Next() {
+		var ts testdata.TestStruct
+		require.NoError(t, it.ScanRow(&ts))
+		dst = append(dst, ts)
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, dst, 2)
+	require.Equal(t, testdata.TestRow1.ID, dst[0].ID)
+	require.Equal(t, testdata.TestRow2.ID, dst[1].ID)
+	require.Equal(t, testdata.TestRow2.JSONB, dst[1].JSONB)
+}
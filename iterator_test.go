@@ -0,0 +1,57 @@
+package pgxscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Iterator_ScanRow(t *testing.T) {
+	src := newGenericUserRows()
+	it := NewIterator(src)
+	defer it.Close()
+
+	var users []genericUser
+	for it.Next() {
+		var user genericUser
+		require.NoError(t, it.ScanRow(&user))
+		users = append(users, user)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []genericUser{{ID: 1, Name: "user01"}, {ID: 2, Name: "user02"}}, users)
+	require.True(t, src.closed)
+}
+
+func Test_Iterator_ScanRowVariadic(t *testing.T) {
+	it := NewIterator(newGenericUserRows())
+	defer it.Close()
+
+	var ids []int64
+	for it.Next() {
+		var (
+			id   int64
+			name string
+		)
+		require.NoError(t, it.ScanRow(&id, &name))
+		ids = append(ids, id)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []int64{1, 2}, ids)
+}
+
+func Test_Iterator_WantErr_UnmappedColumn(t *testing.T) {
+	src := newFakeRows([]string{"id", "name", "email"},
+		[]interface{}{int64(1), "user01", "user01@email.com"},
+		[]interface{}{int64(2), "user02", "user02@email.com"},
+	)
+	it := NewIterator(src)
+	defer it.Close()
+
+	require.True(t, it.Next())
+	var user genericUser
+	err := it.ScanRow(&user)
+	require.EqualError(t, err, `unable to find corresponding field to column "email" returned by query`)
+	require.Equal(t, err, it.Err())
+	require.False(t, it.Next())
+	require.True(t, src.closed)
+}
@@ -0,0 +1,167 @@
+package pgxscan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// jsonConverter returns the converter decoding the json columns of the fields
+// of type t with the json tag option, like `db:"orders,json"`. See
+// Mapper.DecodeJSON.
+func (m *Mapper) jsonConverter(t reflect.Type) *converter {
+	return &converter{name: "json", from: bytesType, to: t, fn: func(src interface{}) (interface{}, error) {
+		dst := reflect.New(t)
+		if err := m.decodeJSON(src.([]byte), dst.Elem()); err != nil {
+			return nil, err
+		}
+		return dst.Elem().Interface(), nil
+	}}
+}
+
+// DecodeJSON decodes data into v, a pointer, using DefaultMapper. See
+// Mapper.DecodeJSON.
+func DecodeJSON(data []byte, v interface{}) error {
+	return DefaultMapper.DecodeJSON(data, v)
+}
+
+// DecodeJSON decodes data into v, a pointer, mapping the keys of the json
+// objects to the fields of the structs in v like columns: the same tags,
+// follow, embed and notate rules and rename function apply, instead of the
+// json tags of encoding/json. It is how the columns of the fields with the
+// json tag option, like `db:"orders,json"`, are decoded, so the objects built
+// by json_agg(row_to_json(o)) map onto the structs of flat queries:
+//
+//	type User struct {
+//	    ID     int64
+//	    Orders []Order `db:"orders,json"`
+//	}
+//
+//	SELECT u.id, (SELECT json_agg(row_to_json(o)) FROM orders o WHERE o.user_id = u.id) AS orders FROM users u
+//
+// The keys of a notated struct are read from a nested object, like
+// {"address": {"city": "city01"}}, or from dotted keys like
+// {"address.city": "city01"}. Keys without a field are ignored. Values that
+// are not mapped structs, like time.Time or the types implementing
+// json.Unmarshaler, are decoded by encoding/json.
+func (m *Mapper) DecodeJSON(data []byte, v interface{}) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return fmt.Errorf("cannot decode json into %T: expecting a non nil pointer", v)
+	}
+	return m.decodeJSON(data, val.Elem())
+}
+
+func (m *Mapper) decodeJSON(data []byte, dst reflect.Value) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var src interface{}
+	if err := dec.Decode(&src); err != nil {
+		return err
+	}
+	return m.assignJSON(src, dst)
+}
+
+// assignJSON sets dst to src, a value decoded by encoding/json with numbers
+// kept as json.Number.
+func (m *Mapper) assignJSON(src interface{}, dst reflect.Value) error {
+	t := dst.Type()
+	switch {
+	case src == nil:
+		dst.Set(reflect.Zero(t))
+		return nil
+	case t.Kind() == reflect.Ptr && !t.Implements(jsonUnmarshalerType):
+		if dst.IsNil() {
+			dst.Set(reflect.New(t.Elem()))
+		}
+		return m.assignJSON(src, dst.Elem())
+	case isJSONStruct(t):
+		obj, ok := src.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cannot decode json %T into %v", src, t)
+		}
+		cm, err := m.columnMap(t)
+		if err != nil {
+			return err
+		}
+		return m.assignJSONObject(obj, "", dst, cm)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		list, ok := src.([]interface{})
+		if !ok {
+			return fmt.Errorf("cannot decode json %T into %v", src, t)
+		}
+		slice := reflect.MakeSlice(t, len(list), len(list))
+		for i, elem := range list {
+			if err := m.assignJSON(elem, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+		return nil
+	}
+	// other values are decoded by encoding/json
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst.Addr().Interface())
+}
+
+// assignJSONObject sets the fields of dst, a struct mapped by cm, to the
+// values of obj whose keys are prefixed with prefix in cm.
+func (m *Mapper) assignJSONObject(obj map[string]interface{}, prefix string, dst reflect.Value, cm sqlmaper.ColumnMap) error {
+	for k, v := range obj {
+		key := prefix + k
+		if data, ok := cm[key]; ok {
+			f := sqlmaper.FieldByIndex(dst, data.FieldIndex)
+			var err error
+			if s, ok := v.(string); ok && data.JSON && !isJSONString(data.GoType) {
+				// a json field of a json object can hold encoded json
+				err = m.decodeJSON([]byte(s), f)
+			} else {
+				err = m.assignJSON(v, f)
+			}
+			if err != nil {
+				return fmt.Errorf("field %s: %w", fieldPath(dst.Type(), data.FieldIndex), err)
+			}
+			continue
+		}
+		if nested, ok := v.(map[string]interface{}); ok && hasColumnPrefix(cm, key+".") {
+			if err := m.assignJSONObject(nested, key+".", dst, cm); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isJSONStruct reports whether the values of type t are decoded by their
+// column map rather than by encoding/json.
+func isJSONStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !sqlmaper.ImplementsScanner(t) && !reflect.PtrTo(t).Implements(jsonUnmarshalerType)
+}
+
+// isJSONString reports whether the json values of type t are strings, in
+// which case a string is not decoded again.
+func isJSONString(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String || t.Kind() == reflect.Interface
+}
+
+// hasColumnPrefix reports whether a column of cm starts with prefix.
+func hasColumnPrefix(cm sqlmaper.ColumnMap, prefix string) bool {
+	for key := range cm {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rows_ScanJSON(t *testing.T) {
+	type Address struct {
+		ID   int64  `db:"id"`
+		City string `db:"city"`
+	}
+	type User struct {
+		ID        int64     `db:"id"`
+		Name      string    `db:"name"`
+		Addresses []Address `db:"addresses,json"`
+	}
+	stmt := `
+	SELECT "u"."id", "u"."name",
+	       (SELECT json_agg(row_to_json("a")) FROM "address" "a" WHERE "a"."user_id" = "u"."id") AS "addresses"
+	FROM "users" "u"
+	WHERE "u"."id" <= $1
+	ORDER BY "u"."id"
+	`
+	rows, err := newTestDB(t).Query(context.Background(), stmt, 1)
+	require.NoError(t, err)
+
+	users, err := pgxscan.All[User](rows)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, "user01", users[0].Name)
+	require.NotEmpty(t, users[0].Addresses)
+	require.Equal(t, "city01", users[0].Addresses[0].City)
+}
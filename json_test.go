@@ -0,0 +1,104 @@
+package pgxscan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	jsonItem struct {
+		SKU      string `db:"sku"`
+		Quantity int
+	}
+	jsonAddress struct {
+		City string
+	}
+	jsonOrder struct {
+		ID        int64        `db:"id"`
+		CreatedAt time.Time    `db:"created_at"`
+		Items     []jsonItem   `db:"items,json"`
+		Shipping  *jsonAddress `db:"shipping,notate"`
+	}
+	jsonUser struct {
+		ID     int64
+		Orders []jsonOrder            `db:"orders,json"`
+		Last   *jsonOrder             `db:"last,json"`
+		Extra  map[string]interface{} `db:"extra,json"`
+	}
+)
+
+func newJSONRows() *fakeRows {
+	src := newFakeRows([]string{"id", "orders", "last", "extra"},
+		[]interface{}{
+			int64(1),
+			[]byte(`[{"id":1,"created_at":"2021-01-02T00:00:00+00:00","items":[{"sku":"a","quantity":2}],"shipping":{"city":"city01"},"total":10},` +
+				`{"id":2,"created_at":"2021-01-03T00:00:00+00:00","items":"[{\"sku\":\"b\",\"quantity\":1}]","shipping.city":"city02"}]`),
+			[]byte(`{"id":2,"items":null,"shipping":null}`),
+			[]byte(`{"a":1}`),
+		},
+		[]interface{}{int64(2), nil, nil, nil},
+	)
+	src.fields[1].DataTypeOID = pgtype.JSONOID
+	src.fields[2].DataTypeOID = pgtype.JSONBOID
+	src.fields[3].DataTypeOID = pgtype.JSONBOID
+	return src
+}
+
+func Test_rows_ScanJSON(t *testing.T) {
+	var users []jsonUser
+	require.NoError(t, NewScanner(newJSONRows()).Scan(&users))
+	require.Equal(t, []jsonUser{
+		{
+			ID: 1,
+			Orders: []jsonOrder{
+				{
+					ID: 1, CreatedAt: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+					Items:    []jsonItem{{SKU: "a", Quantity: 2}},
+					Shipping: &jsonAddress{City: "city01"},
+				},
+				{
+					ID: 2, CreatedAt: time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+					Items:    []jsonItem{{SKU: "b", Quantity: 1}},
+					Shipping: &jsonAddress{City: "city02"},
+				},
+			},
+			Last:  &jsonOrder{ID: 2},
+			Extra: map[string]interface{}{"a": float64(1)},
+		},
+		{ID: 2},
+	}, normalizeJSONUsers(users))
+}
+
+// normalizeJSONUsers sets the locations of the decoded times to UTC.
+func normalizeJSONUsers(users []jsonUser) []jsonUser {
+	for i := range users {
+		for j := range users[i].Orders {
+			users[i].Orders[j].CreatedAt = users[i].Orders[j].CreatedAt.UTC()
+		}
+	}
+	return users
+}
+
+func Test_DecodeJSON(t *testing.T) {
+	mapper := NewMapper(RenameFunc(func(name string) string { return "x_" + SnakeCase(name) }))
+	var item jsonItem
+	require.NoError(t, mapper.DecodeJSON([]byte(`{"sku":"a","x_quantity":3,"quantity":4}`), &item))
+	require.Equal(t, jsonItem{SKU: "a", Quantity: 3}, item)
+
+	require.EqualError(t, DecodeJSON([]byte(`{}`), item), "cannot decode json into pgxscan.jsonItem: expecting a non nil pointer")
+}
+
+func Test_rows_ScanJSON_WantErr(t *testing.T) {
+	src := newFakeRows([]string{"id", "orders"}, []interface{}{int64(1), []byte(`[{"id":"one"}]`)})
+	src.fields[1].DataTypeOID = pgtype.JSONOID
+	var user jsonUser
+	err := NewScanner(src).Scan(&user)
+	var scanErr *ScanError
+	require.True(t, errors.As(err, &scanErr))
+	require.Equal(t, "Orders", scanErr.Field)
+	require.Contains(t, scanErr.Err.Error(), "field ID: json: cannot unmarshal string into Go value of type int64")
+}
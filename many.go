@@ -0,0 +1,90 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+)
+
+// aggregator collapses the rows of a one to many join into their parent
+// structs. Rows sharing the pk of a plan are merged into the first one, and
+// the elements of its many fields are appended to its slices unless an
+// element with the same pk was already appended. Rows do not need to be
+// ordered.
+type aggregator struct {
+	plan *scanPlan
+	// seen holds the position in its slice of each value by pk, the pk of an
+	// element of a many field is prefixed by the pk of its parents.
+	seen map[aggregateKey]int
+}
+
+type aggregateKey struct {
+	plan *scanPlan
+	key  string
+}
+
+func newAggregator(plan *scanPlan) *aggregator {
+	return &aggregator{plan: plan, seen: make(map[aggregateKey]int)}
+}
+
+// add appends row, a pointer to a struct scanned with the plan of a, to the
+// slice list or merges it into the element with the same pk.
+func (a *aggregator) add(list, row reflect.Value) {
+	if list.Type().Elem().Kind() != reflect.Ptr {
+		row = row.Elem()
+	}
+	a.append(a.plan, list, row, "")
+}
+
+func (a *aggregator) append(p *scanPlan, list, item reflect.Value, parentKey string) {
+	src := reflect.Indirect(item)
+	key, ok := p.key(src)
+	if !ok {
+		// without pk every element is kept
+		list.Set(reflect.Append(list, item))
+		return
+	}
+	key = parentKey + key
+	at, seen := a.seen[aggregateKey{p, key}]
+	if !seen {
+		at = list.Len()
+		a.seen[aggregateKey{p, key}] = at
+		// the appended value starts with empty many fields, the elements
+		// of the row are merged below like the ones of the following rows
+		elems := reflect.New(src.Type()).Elem()
+		elems.Set(src)
+		for _, many := range p.many {
+			f := sqlmaper.FieldByIndex(src, many.index)
+			f.Set(reflect.Zero(f.Type()))
+		}
+		list.Set(reflect.Append(list, item))
+		src = elems
+	}
+	dst := reflect.Indirect(list.Index(at))
+	for _, many := range p.many {
+		from := sqlmaper.FieldByIndex(src, many.index)
+		to := sqlmaper.FieldByIndex(dst, many.index)
+		for i := 0; i < from.Len(); i++ {
+			a.append(many.plan, to, from.Index(i), key)
+		}
+	}
+}
+
+// key returns the values of the pk columns of dst, a struct scanned with p.
+// ok is false when the result set has no pk column for p.
+func (p *scanPlan) key(dst reflect.Value) (key string, ok bool) {
+	if len(p.pk) == 0 {
+		return "", false
+	}
+	var b strings.Builder
+	for _, idx := range p.pk {
+		if v := reflect.Indirect(sqlmaper.FieldByIndex(dst, p.fields[idx])); v.IsValid() {
+			fmt.Fprintf(&b, "%v\x00", v.Interface())
+		} else {
+			b.WriteString("<nil>\x00")
+		}
+	}
+	return b.String(), true
+}
@@ -0,0 +1,43 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rows_ScanMany(t *testing.T) {
+	type (
+		Tag struct {
+			ID int `db:"id,pk"`
+		}
+		User struct {
+			ID   uint32 `db:"id,pk"`
+			Name string
+			Tags []Tag `db:"tags,many"`
+		}
+	)
+	stmt := `
+	SELECT users.id, users.name,
+	       0 AS "notate:tags",
+	       tag AS id
+	FROM users
+	CROSS JOIN generate_series(1, 2) tag
+	WHERE users.id <= $1
+	ORDER BY tag, users.id
+	`
+	rows, err := newTestDB(t).Query(context.Background(), stmt, 2)
+	require.NoError(t, err)
+
+	var users []User
+	require.NoError(t, pgxscan.NewScanner(rows).Scan(&users))
+	require.Equal(t, []User{
+		{ID: 1, Name: "user01", Tags: []Tag{{ID: 1}, {ID: 2}}},
+		{ID: 2, Name: "user02", Tags: []Tag{{ID: 1}, {ID: 2}}},
+	}, users)
+}
@@ -0,0 +1,79 @@
+package pgxscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	manyItem struct {
+		ID   int64 `db:"id,pk"`
+		Name string
+	}
+	manyOrder struct {
+		ID    int64       `db:"id,pk"`
+		Total int64       `db:"total"`
+		Items []*manyItem `db:"items,many"`
+	}
+	manyUser struct {
+		ID     int64       `db:"id,pk"`
+		Name   string      `db:"name"`
+		Orders []manyOrder `db:"orders,many"`
+	}
+)
+
+func newManyUserRows() *fakeRows {
+	return newFakeRows([]string{"id", "name", "orders.id", "orders.total", "orders.items.id", "orders.items.name"},
+		[]interface{}{int64(1), "user01", int64(10), int64(100), int64(100), "item01"},
+		[]interface{}{int64(1), "user01", int64(10), int64(100), int64(101), "item02"},
+		[]interface{}{int64(2), "user02", nil, nil, nil, nil},
+		[]interface{}{int64(1), "user01", int64(11), int64(110), nil, nil},
+		[]interface{}{int64(1), "user01", int64(10), int64(100), int64(100), "item01"},
+	)
+}
+
+func Test_rows_ScanMany(t *testing.T) {
+	var users []manyUser
+	require.NoError(t, NewScanner(newManyUserRows()).Scan(&users))
+	require.Equal(t, []manyUser{
+		{ID: 1, Name: "user01", Orders: []manyOrder{
+			{ID: 10, Total: 100, Items: []*manyItem{{ID: 100, Name: "item01"}, {ID: 101, Name: "item02"}}},
+			{ID: 11, Total: 110},
+		}},
+		{ID: 2, Name: "user02"},
+	}, users)
+
+	var ptrs []*manyUser
+	require.NoError(t, NewScanner(newManyUserRows()).Scan(&ptrs))
+	require.Len(t, ptrs, 2)
+	require.Equal(t, users[0], *ptrs[0])
+}
+
+func Test_rows_ScanMany_Struct(t *testing.T) {
+	src := newFakeRows([]string{"id", "name", "notate:orders", "id", "total"},
+		[]interface{}{int64(1), "user01", 0, int64(10), int64(100)},
+		[]interface{}{int64(1), "user01", 0, int64(11), int64(110)},
+	)
+	var user manyUser
+	require.NoError(t, ScanOne(src, &user))
+	require.Equal(t, manyUser{ID: 1, Name: "user01", Orders: []manyOrder{{ID: 10, Total: 100}, {ID: 11, Total: 110}}}, user)
+
+	err := ScanOne(newManyUserRows(), &user)
+	require.Equal(t, ErrTooManyRows, err)
+
+	require.NoError(t, ScanFirst(newManyUserRows(), &user))
+	require.Equal(t, int64(1), user.ID)
+	require.Len(t, user.Orders, 1)
+}
+
+func Test_rows_ScanMany_WantErr_NoPK(t *testing.T) {
+	type User struct {
+		ID     int64
+		Orders []manyOrder `db:"orders,many"`
+	}
+	src := newFakeRows([]string{"id", "orders.id"}, []interface{}{int64(1), int64(10)})
+	var users []User
+	err := NewScanner(src).Scan(&users)
+	require.EqualError(t, err, "many field Orders of pgxscan.User requires a pk column to group rows")
+}
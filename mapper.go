@@ -0,0 +1,164 @@
+package pgxscan
+
+import (
+	"reflect"
+	"sync"
+
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+)
+
+// Mapper holds the conventions used to map result columns to struct fields:
+// the struct tag name, the rename function for untagged fields, whether
+// nested structs are notated by default and the column notate prefix.
+// Column maps and scan plans are cached per Mapper, so two libraries in the
+// same binary can use different conventions.
+//
+//	mapper := pgxscan.NewMapper(pgxscan.TagName("sql"), pgxscan.RenameFunc(strings.ToLower))
+//	err := pgxscan.NewScanner(rows, pgxscan.UseMapper(mapper)).Scan(&dst)
+type Mapper struct {
+	mapper *sqlmaper.Mapper
+	// prefix is the column notate prefix, QueryColumnNotatePrefix is used
+	// when it is empty.
+	prefix string
+	plans  sync.Map
+
+	convMu     sync.RWMutex
+	converters []*converter
+	named      map[string]*converter
+}
+
+// DefaultMapper is the Mapper used when no UseMapper option is given. It maps
+// fields with the "db" tag, renames untagged fields to snake case and uses
+// QueryColumnNotatePrefix as the notate prefix.
+var DefaultMapper = newMapper(sqlmaper.DefaultMapper(), "")
+
+type mapperConfig struct {
+	opts   sqlmaper.MapperOptions
+	prefix string
+}
+
+// MapperOption configures a Mapper created by NewMapper.
+type MapperOption interface {
+	applyMapper(*mapperConfig)
+}
+
+// mapperOptionFunc wraps a func so it satisfies the MapperOption interface.
+type mapperOptionFunc func(*mapperConfig)
+
+func (f mapperOptionFunc) applyMapper(cfg *mapperConfig) {
+	f(cfg)
+}
+
+// NewMapper returns a Mapper with its own settings and cache. Settings that
+// are not given use the same defaults as DefaultMapper.
+func NewMapper(opts ...MapperOption) *Mapper {
+	cfg := &mapperConfig{}
+	for _, opt := range opts {
+		opt.applyMapper(cfg)
+	}
+	return newMapper(sqlmaper.NewMapper(cfg.opts), cfg.prefix)
+}
+
+func newMapper(mapper *sqlmaper.Mapper, prefix string) *Mapper {
+	m := &Mapper{mapper: mapper, prefix: prefix}
+	// the plans are compiled from the column maps of mapper
+	mapper.OnUpdate(m.clearPlans)
+	return m
+}
+
+// clearPlans drops the scan plans compiled so far.
+func (m *Mapper) clearPlans() {
+	m.plans.Range(func(key, _ interface{}) bool {
+		m.plans.Delete(key)
+		return true
+	})
+}
+
+// TagName sets the struct tag holding the column names. Defaults to "db".
+// Options can also be set with the "scan" tag, whatever the tag name is.
+func TagName(name string) MapperOption {
+	return mapperOptionFunc(func(cfg *mapperConfig) {
+		cfg.opts.TagName = name
+	})
+}
+
+// RenameFunc sets the function returning the column name of a field that has
+// no named tag. Defaults to SnakeCase.
+func RenameFunc(fn func(string) string) MapperOption {
+	return mapperOptionFunc(func(cfg *mapperConfig) {
+		cfg.opts.ColumnRename = fn
+	})
+}
+
+// NotateByDefault sets whether nested struct fields are notated even when
+// they do not have the notate tag option.
+func NotateByDefault(b bool) MapperOption {
+	return mapperOptionFunc(func(cfg *mapperConfig) {
+		cfg.opts.NotateByDefault = b
+	})
+}
+
+// MaxDepth sets the maximum number of nested struct levels mapped below a
+// struct, DefaultMaxDepth by default. Mapping a struct nested deeper returns
+// an error, except for self-referential structs, which are mapped n levels
+// deep so bounded self joins like "manager.manager.name" can be scanned.
+// Without MaxDepth, self-referential structs return an error.
+//
+//	type Employee struct {
+//	    ID      int64
+//	    Name    string
+//	    Manager *Employee `db:"manager,notate"`
+//	}
+//
+//	mapper := pgxscan.NewMapper(pgxscan.MaxDepth(2))
+func MaxDepth(n int) MapperOption {
+	return mapperOptionFunc(func(cfg *mapperConfig) {
+		cfg.opts.MaxDepth = n
+	})
+}
+
+// RejectAmbiguousColumns sets whether mapping a struct returns an error when
+// two fields at the same depth map the same column, like the ID fields of two
+// embedded structs. Otherwise the Go rules apply: the shallowest field is
+// mapped, and the first one declared among fields at the same depth.
+func RejectAmbiguousColumns(b bool) MapperOption {
+	return mapperOptionFunc(func(cfg *mapperConfig) {
+		cfg.opts.RejectAmbiguous = b
+	})
+}
+
+// DefaultMaxDepth is the maximum number of nested struct levels mapped by a
+// Mapper without the MaxDepth option.
+const DefaultMaxDepth = sqlmaper.DefaultMaxDepth
+
+// NotatePrefix sets the prefix of the notate columns described in
+// GetColumnNames. Defaults to "notate:".
+func NotatePrefix(prefix string) MapperOption {
+	return mapperOptionFunc(func(cfg *mapperConfig) {
+		cfg.prefix = prefix
+	})
+}
+
+// SnakeCase converts a field name like "HTTPServer" to "http_server".
+func SnakeCase(name string) string {
+	return sqlmaper.SnakeCase(name)
+}
+
+// UseMapper sets the Mapper used to map columns to struct fields.
+func UseMapper(m *Mapper) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Mapper = m
+	})
+}
+
+func (m *Mapper) notatePrefix() string {
+	if m.prefix == "" {
+		return QueryColumnNotatePrefix
+	}
+	return m.prefix
+}
+
+// columnMap returns the column map of the struct type t.
+func (m *Mapper) columnMap(t reflect.Type) (sqlmaper.ColumnMap, error) {
+	return m.mapper.GetColumnMap(reflect.New(t).Interface())
+}
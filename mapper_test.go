@@ -0,0 +1,141 @@
+package pgxscan
+
+import (
+	"strings"
+	"testing"
+
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Mapper_TagNameAndRenameFunc(t *testing.T) {
+	type User struct {
+		UserID   int64 `sql:"id"`
+		UserName string
+	}
+	mapper := NewMapper(TagName("sql"), RenameFunc(strings.ToLower))
+	src := newFakeRows([]string{"id", "username"}, []interface{}{int64(1), "user01"})
+
+	var user User
+	err := NewScanner(src, UseMapper(mapper)).Scan(&user)
+	require.NoError(t, err)
+	require.Equal(t, User{UserID: 1, UserName: "user01"}, user)
+
+	// the default mapper is not affected by the custom one
+	src = newFakeRows([]string{"user_id", "user_name"}, []interface{}{int64(1), "user01"})
+	user = User{}
+	err = NewScanner(src).Scan(&user)
+	require.NoError(t, err)
+	require.Equal(t, User{UserID: 1, UserName: "user01"}, user)
+}
+
+func Test_Mapper_NotateByDefaultAndPrefix(t *testing.T) {
+	type (
+		Address struct {
+			ID   int64
+			City string
+		}
+		User struct {
+			ID      int64
+			Address Address
+		}
+	)
+	mapper := NewMapper(NotateByDefault(true), NotatePrefix("nested:"))
+	src := newFakeRows([]string{"id", "nested:address", "id", "city"},
+		[]interface{}{int64(1), int64(0), int64(2), "city01"},
+	)
+
+	var users []User
+	err := NewScanner(src, UseMapper(mapper)).Scan(&users)
+	require.NoError(t, err)
+	require.Equal(t, []User{{ID: 1, Address: Address{ID: 2, City: "city01"}}}, users)
+
+	cols, err := mapper.notateColumns([]string{"id", "nested:address", "id", "notate:x"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"id", "nested:address", "address.id", "address.notate:x"}, cols)
+}
+
+func Test_Mapper_Generic(t *testing.T) {
+	type User struct {
+		UserID int64 `sql:"id"`
+	}
+	mapper := NewMapper(TagName("sql"))
+	users, err := All[User](newFakeRows([]string{"id"}, []interface{}{int64(1)}), UseMapper(mapper))
+	require.NoError(t, err)
+	require.Equal(t, []User{{UserID: 1}}, users)
+}
+
+func Test_Mapper_UpdateDropsPlans(t *testing.T) {
+	type User struct {
+		UserName string
+	}
+	var user User
+	require.NoError(t, NewScanner(newFakeRows([]string{"user_name"}, []interface{}{"user01"})).Scan(&user))
+	require.Equal(t, User{UserName: "user01"}, user)
+
+	sqlmaper.SetColumnRenameFunction(strings.ToUpper)
+	defer sqlmaper.SetColumnRenameFunction(SnakeCase)
+
+	// the plan compiled with the previous rename function is not used
+	user = User{}
+	require.NoError(t, NewScanner(newFakeRows([]string{"USERNAME"}, []interface{}{"user02"})).Scan(&user))
+	require.Equal(t, User{UserName: "user02"}, user)
+	user = User{}
+	err := NewScanner(newFakeRows([]string{"user_name"}, []interface{}{"user03"})).Scan(&user)
+	require.Error(t, err)
+}
+
+type depthEmployee struct {
+	ID      int64
+	Name    string
+	Manager *depthEmployee `db:"manager,notate"`
+}
+
+func Test_Mapper_MaxDepth(t *testing.T) {
+	newRows := func() *fakeRows {
+		return newFakeRows([]string{"id", "name", "notate:manager", "id", "name", "notate:manager.manager", "name"},
+			[]interface{}{int64(3), "employee03", 0, int64(2), "employee02", 0, "employee01"},
+		)
+	}
+	var employee depthEmployee
+	err := ScanOne(newRows(), &employee)
+	require.EqualError(t, err, "field Manager of pgxscan.depthEmployee refers back to pgxscan.depthEmployee, set a max depth to map self-referential structs")
+
+	mapper := NewMapper(MaxDepth(2))
+	require.NoError(t, ScanOne(newRows(), &employee, UseMapper(mapper)))
+	require.Equal(t, depthEmployee{ID: 3, Name: "employee03", Manager: &depthEmployee{
+		ID: 2, Name: "employee02", Manager: &depthEmployee{Name: "employee01"},
+	}}, employee)
+
+	cols, err := mapper.SelectColumns(depthEmployee{}, "e", Aliases{"manager": "m", "manager.manager": "mm"})
+	require.NoError(t, err)
+	require.Equal(t, `"e"."id", "e"."name", 0 AS "notate:manager", "m"."id", "m"."name", 0 AS "notate:manager.manager", "mm"."id", "mm"."name"`, cols)
+}
+
+type (
+	ambiguousUser struct {
+		ID   int64
+		Name string
+	}
+	ambiguousOrder struct {
+		ID    int64
+		Total int64
+	}
+	ambiguousRow struct {
+		ambiguousUser
+		ambiguousOrder
+	}
+)
+
+func Test_Mapper_RejectAmbiguousColumns(t *testing.T) {
+	newRows := func() *fakeRows {
+		return newFakeRows([]string{"id", "name", "total"}, []interface{}{int64(1), "user01", int64(10)})
+	}
+	var row ambiguousRow
+	require.NoError(t, ScanOne(newRows(), &row))
+	require.Equal(t, ambiguousRow{ambiguousUser{ID: 1, Name: "user01"}, ambiguousOrder{Total: 10}}, row)
+
+	mapper := NewMapper(RejectAmbiguousColumns(true))
+	err := ScanOne(newRows(), &row, UseMapper(mapper))
+	require.EqualError(t, err, `column "id" of pgxscan.ambiguousRow is ambiguous, it is mapped by fields ambiguousUser.ID and ambiguousOrder.ID`)
+}
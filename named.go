@@ -0,0 +1,240 @@
+package pgxscan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+)
+
+// Execer runs a statement that returns no rows with Exec.
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+// Named rewrites the :name and @name placeholders of sql into positional
+// ones and returns the matching arguments taken from arg, using DefaultMapper.
+// See Mapper.Named.
+//
+//	sql, args, err := pgxscan.Named(`UPDATE users SET name = :name WHERE id = :id`, user)
+//	_, err = conn.Exec(ctx, sql, args...)
+func Named(sql string, arg interface{}) (string, []interface{}, error) {
+	return DefaultMapper.Named(sql, arg)
+}
+
+// Named rewrites the :name and @name placeholders of sql into positional
+// ones and returns the matching arguments taken from arg.
+//
+// arg is a struct, or a pointer to one, whose fields are named like the
+// columns they are scanned from: the same tags, follow, embed and notate
+// rules and rename function apply, so nested fields are named like
+// :address.city. arg can also be a map with string keys.
+//
+// A name used more than once is bound to a single argument. Placeholders in
+// string literals, including the E'...' ones with backslash escapes, quoted
+// identifiers and comments are left alone, and so are casts like "::text".
+// sql cannot also have positional parameters like $1, they would clash with
+// the ones of the names.
+func (m *Mapper) Named(sql string, arg interface{}) (string, []interface{}, error) {
+	lookup, err := m.namedLookup(arg)
+	if err != nil {
+		return "", nil, err
+	}
+	var (
+		b     strings.Builder
+		args  []interface{}
+		bound = map[string]int{}
+	)
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := skipQuoted(sql, i, c, c == '\'' && isEscapeString(sql, i))
+			b.WriteString(sql[i:end])
+			i = end
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			b.WriteString(sql[i : i+end])
+			i += end
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				end = len(sql) - i
+			} else {
+				end += 4
+			}
+			b.WriteString(sql[i : i+end])
+			i += end
+		case c == '$' && dollarQuote(sql[i:]) != "":
+			tag := dollarQuote(sql[i:])
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				end = len(sql) - i
+			} else {
+				end += 2 * len(tag)
+			}
+			b.WriteString(sql[i : i+end])
+			i += end
+		case c == '$' && i+1 < len(sql) && isDigit(sql[i+1]) && (i == 0 || !isIdentPart(sql[i-1])):
+			end := i + 1
+			for end < len(sql) && isDigit(sql[end]) {
+				end++
+			}
+			return "", nil, fmt.Errorf("cannot bind named parameters to a query with positional parameter %s", sql[i:end])
+		case c == ':' && strings.HasPrefix(sql[i:], "::"):
+			b.WriteString("::")
+			i += 2
+		case (c == ':' || c == '@') && i+1 < len(sql) && isNameStart(sql[i+1]):
+			end := i + 1
+			for end < len(sql) && isNamePart(sql[end]) {
+				end++
+			}
+			name := strings.TrimRight(sql[i+1:end], ".")
+			end = i + 1 + len(name)
+			n, ok := bound[name]
+			if !ok {
+				value, found := lookup(name)
+				if !found {
+					return "", nil, fmt.Errorf(`could not find name "%s" in %T`, name, arg)
+				}
+				args = append(args, value)
+				n = len(args)
+				bound[name] = n
+			}
+			b.WriteString("$" + strconv.Itoa(n))
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), args, nil
+}
+
+// namedLookup returns the func returning the value of a name in arg.
+func (m *Mapper) namedLookup(arg interface{}) (func(string) (interface{}, bool), error) {
+	val := reflect.ValueOf(arg)
+	for val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	switch {
+	case val.Kind() == reflect.Map && val.Type().Key().Kind() == reflect.String:
+		return func(name string) (interface{}, bool) {
+			v := val.MapIndex(reflect.ValueOf(name).Convert(val.Type().Key()))
+			if !v.IsValid() {
+				return nil, false
+			}
+			return v.Interface(), true
+		}, nil
+	case val.Kind() == reflect.Struct && !sqlmaper.ImplementsScanner(val.Type()):
+		cm, err := m.columnMap(val.Type())
+		if err != nil {
+			return nil, err
+		}
+		return func(name string) (interface{}, bool) {
+			data, ok := cm[name]
+			if !ok {
+				return nil, false
+			}
+			if f, ok := sqlmaper.SafeGetFieldByIndex(val, data.FieldIndex); ok {
+				return f.Interface(), true
+			}
+			// a nil pointer to a nested struct binds NULL
+			return nil, true
+		}, nil
+	}
+	return nil, fmt.Errorf("named arguments must be a struct or a map with string keys, got %T", arg)
+}
+
+// skipQuoted returns the position following the literal or identifier quoted
+// with q starting at i, doubled quotes being escaped ones. Backslashes escape
+// the following character when backslash is set.
+func skipQuoted(sql string, i int, q byte, backslash bool) int {
+	for j := i + 1; j < len(sql); j++ {
+		if backslash && sql[j] == '\\' {
+			j++
+			continue
+		}
+		if sql[j] == q {
+			if j+1 < len(sql) && sql[j+1] == q {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(sql)
+}
+
+// dollarQuote returns the $tag$ opening a dollar quoted string at the start
+// of s, if any.
+func dollarQuote(s string) string {
+	for j := 1; j < len(s); j++ {
+		switch {
+		case s[j] == '$':
+			return s[:j+1]
+		case isNameStart(s[j]), j > 1 && s[j] >= '0' && s[j] <= '9':
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// isEscapeString reports whether the literal quoted at i is an escape string
+// constant, like E'it\'s'.
+func isEscapeString(sql string, i int) bool {
+	if i == 0 || (sql[i-1] != 'E' && sql[i-1] != 'e') {
+		return false
+	}
+	return i == 1 || !isIdentPart(sql[i-2])
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isIdentPart reports whether c can be part of an unquoted identifier.
+func isIdentPart(c byte) bool {
+	return isNameStart(c) || isDigit(c) || c == '$'
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || isDigit(c) || c == '.'
+}
+
+// NamedQuery binds the named placeholders of sql to arg, see Named, and runs
+// the query on q.
+//
+//	rows, err := pgxscan.NamedQuery(ctx, conn, `SELECT * FROM users WHERE name = :name`, filter)
+func NamedQuery(ctx context.Context, q Querier, sql string, arg interface{}) (pgx.Rows, error) {
+	sql, args, err := Named(sql, arg)
+	if err != nil {
+		return nil, err
+	}
+	return q.Query(ctx, sql, args...)
+}
+
+// NamedExec binds the named placeholders of sql to arg, see Named, and runs
+// the statement on e.
+//
+//	_, err := pgxscan.NamedExec(ctx, conn, `INSERT INTO users (name, email) VALUES (:name, :email)`, user)
+func NamedExec(ctx context.Context, e Execer, sql string, arg interface{}) (pgconn.CommandTag, error) {
+	sql, args, err := Named(sql, arg)
+	if err != nil {
+		return nil, err
+	}
+	return e.Exec(ctx, sql, args...)
+}
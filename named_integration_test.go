@@ -0,0 +1,37 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NamedQuery(t *testing.T) {
+	rows, err := pgxscan.NamedQuery(context.Background(), testDB, `SELECT "id", "name", "email" FROM "users" WHERE "name" = :name AND "email" = :email`,
+		user{Name: "user02", Email: "user02@email.com"})
+	require.NoError(t, err)
+	u, err := pgxscan.One[user](rows)
+	require.NoError(t, err)
+	require.Equal(t, user{ID: 2, Name: "user02", Email: "user02@email.com"}, u)
+}
+
+func Test_NamedExec(t *testing.T) {
+	tx, err := testDB.Begin(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+
+	tag, err := pgxscan.NamedExec(context.Background(), tx, `UPDATE "users" SET "name" = :name WHERE "id" = :id`,
+		map[string]interface{}{"id": 1, "name": "renamed"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), tag.RowsAffected())
+
+	var name string
+	err = pgxscan.Get(context.Background(), tx, &name, `SELECT "name" FROM "users" WHERE "id" = $1`, 1)
+	require.NoError(t, err)
+	require.Equal(t, "renamed", name)
+}
@@ -0,0 +1,103 @@
+package pgxscan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	namedAddress struct {
+		City string
+	}
+	namedUser struct {
+		ID      int64         `db:"id"`
+		Name    string        `db:"name"`
+		Address *namedAddress `db:"address,notate"`
+	}
+)
+
+func Test_Named_Struct(t *testing.T) {
+	user := namedUser{ID: 1, Name: "user01", Address: &namedAddress{City: "city01"}}
+	sql, args, err := Named(`UPDATE users SET name = :name, city = @address.city WHERE id = :id AND :id > 0`, &user)
+	require.NoError(t, err)
+	require.Equal(t, `UPDATE users SET name = $1, city = $2 WHERE id = $3 AND $3 > 0`, sql)
+	require.Equal(t, []interface{}{"user01", "city01", int64(1)}, args)
+
+	// a nil nested struct binds NULL
+	_, args, err = Named(`SELECT :address.city`, namedUser{})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{nil}, args)
+}
+
+func Test_Named_Map(t *testing.T) {
+	sql, args, err := Named(`SELECT * FROM users WHERE name = :name AND id < :id.`, map[string]interface{}{"name": "user01", "id": 3})
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM users WHERE name = $1 AND id < $2.`, sql)
+	require.Equal(t, []interface{}{"user01", 3}, args)
+}
+
+func Test_Named_SkipsLiteralsAndCasts(t *testing.T) {
+	query := `SELECT ':name', "@name", $$:name$$, $tag$ :name $tag$, :id::text, a @> b -- :name
+/* @name */ FROM users WHERE id = @id`
+	sql, args, err := Named(query, map[string]interface{}{"id": 1})
+	require.NoError(t, err)
+	require.Equal(t, `SELECT ':name', "@name", $$:name$$, $tag$ :name $tag$, $1::text, a @> b -- :name
+/* @name */ FROM users WHERE id = $1`, sql)
+	require.Equal(t, []interface{}{1}, args)
+}
+
+func Test_Named_EscapeString(t *testing.T) {
+	sql, args, err := Named(`SELECT E'it\'s :name', e'\\', :id, type'x'`, map[string]interface{}{"id": 1})
+	require.NoError(t, err)
+	require.Equal(t, `SELECT E'it\'s :name', e'\\', $1, type'x'`, sql)
+	require.Equal(t, []interface{}{1}, args)
+}
+
+func Test_Named_WantErr(t *testing.T) {
+	_, _, err := Named(`SELECT :email`, namedUser{})
+	require.EqualError(t, err, `could not find name "email" in pgxscan.namedUser`)
+
+	_, _, err = Named(`SELECT :id`, 1)
+	require.EqualError(t, err, "named arguments must be a struct or a map with string keys, got int")
+
+	_, _, err = Named(`SELECT * FROM users WHERE id = $1 AND name = :name`, namedUser{})
+	require.EqualError(t, err, "cannot bind named parameters to a query with positional parameter $1")
+
+	// positional parameters in literals, comments and identifiers are not ones
+	sql, _, err := Named(`SELECT '$1', col$1 /* $2 */ FROM users WHERE name = :name`, namedUser{})
+	require.NoError(t, err)
+	require.Equal(t, `SELECT '$1', col$1 /* $2 */ FROM users WHERE name = $1`, sql)
+}
+
+type fakeExecer struct {
+	sql  string
+	args []interface{}
+}
+
+func (e *fakeExecer) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	e.sql, e.args = sql, args
+	return pgconn.CommandTag("UPDATE 1"), nil
+}
+
+func Test_NamedQuery(t *testing.T) {
+	q := &fakeQuerier{rows: newGenericUserRows()}
+	rows, err := NamedQuery(context.Background(), q, `SELECT id, name FROM users WHERE id < :id`, map[string]interface{}{"id": 3})
+	require.NoError(t, err)
+	users, err := All[genericUser](rows)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, `SELECT id, name FROM users WHERE id < $1`, q.sql)
+	require.Equal(t, []interface{}{3}, q.args)
+}
+
+func Test_NamedExec(t *testing.T) {
+	e := &fakeExecer{}
+	tag, err := NamedExec(context.Background(), e, `UPDATE users SET name = :name WHERE id = :id`, namedUser{ID: 1, Name: "user01"})
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE 1", tag.String())
+	require.Equal(t, `UPDATE users SET name = $1 WHERE id = $2`, e.sql)
+	require.Equal(t, []interface{}{"user01", int64(1)}, e.args)
+}
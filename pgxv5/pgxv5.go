@@ -0,0 +1,189 @@
+// Package pgxv5 scans the rows of pgx v5 with the mapping rules of pgxscan.
+// Struct tags, options and Mappers are the ones of pgxscan, the values are
+// scanned by pgx v5.
+//
+//	rows, _ := conn.Query(ctx, `SELECT * FROM users`)
+//	users, err := pgx.CollectRows(rows, pgxv5.RowToStructByTag[User])
+package pgxv5
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	pgxv4 "github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/randallmlough/pgxscan"
+)
+
+// NewScanner returns a pgxscan.Scanner reading src.
+func NewScanner(src pgx.Rows, opts ...pgxscan.Option) pgxscan.Scanner {
+	return &scanner{scanner: pgxscan.NewScanner(newRows(src), opts...)}
+}
+
+type scanner struct {
+	scanner pgxscan.Scanner
+}
+
+func (s *scanner) Scan(v ...interface{}) error {
+	return convertErr(s.scanner.Scan(v...))
+}
+
+// All scans every row of src into a new slice of T and closes src, see
+// pgxscan.All.
+func All[T any](src pgx.Rows, opts ...pgxscan.Option) ([]T, error) {
+	dst, err := pgxscan.All[T](newRows(src), opts...)
+	return dst, convertErr(err)
+}
+
+// One scans the single row of src into a new T and closes src, see
+// pgxscan.One. pgx.ErrNoRows is returned when there is no row.
+func One[T any](src pgx.Rows, opts ...pgxscan.Option) (T, error) {
+	dst, err := pgxscan.One[T](newRows(src), opts...)
+	return dst, convertErr(err)
+}
+
+// Querier runs a query with Query and returns its pgx v5 rows.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Select runs sql on q and scans every returned row into dst, see
+// pgxscan.Select.
+func Select(ctx context.Context, q Querier, dst interface{}, sql string, args ...interface{}) error {
+	return convertErr(pgxscan.Select(ctx, querier{q}, dst, sql, args...))
+}
+
+// Get runs sql on q and scans the single returned row into dst, see
+// pgxscan.Get.
+func Get(ctx context.Context, q Querier, dst interface{}, sql string, args ...interface{}) error {
+	return convertErr(pgxscan.Get(ctx, querier{q}, dst, sql, args...))
+}
+
+// querier adapts a Querier to pgxscan.Querier.
+type querier struct {
+	q Querier
+}
+
+func (q querier) Query(ctx context.Context, sql string, args ...interface{}) (pgxv4.Rows, error) {
+	src, err := q.q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return newRows(src), nil
+}
+
+// RowToStructByTag scans the current row into a T, a struct mapped with the
+// pgxscan rules. It is a pgx.RowToFunc, to be used with pgx.CollectRows and
+// pgx.CollectOneRow:
+//
+//	users, err := pgx.CollectRows(rows, pgxv5.RowToStructByTag[User])
+func RowToStructByTag[T any](row pgx.CollectableRow) (T, error) {
+	return scanRowTo[T](defaultPool[T](), row)
+}
+
+// RowToAddrOfStructByTag is like RowToStructByTag but returns a *T.
+func RowToAddrOfStructByTag[T any](row pgx.CollectableRow) (*T, error) {
+	return scanRowTo[*T](defaultPool[*T](), row)
+}
+
+// RowTo returns a pgx.RowToFunc scanning the current row into a T with opts.
+// The column list and scan plan are compiled for the first row of a result
+// and reused for the following rows with the same columns.
+func RowTo[T any](opts ...pgxscan.Option) pgx.RowToFunc[T] {
+	pool := newScannerPool(opts)
+	return func(row pgx.CollectableRow) (T, error) {
+		return scanRowTo[T](pool, row)
+	}
+}
+
+// defaultPools holds the scanner pool of RowToStructByTag by destination
+// type, a pgx.RowToFunc having nowhere else to keep it.
+var defaultPools sync.Map
+
+func defaultPool[T any]() *sync.Pool {
+	key := reflect.TypeOf((*T)(nil)).Elem()
+	if pool, ok := defaultPools.Load(key); ok {
+		return pool.(*sync.Pool)
+	}
+	pool, _ := defaultPools.LoadOrStore(key, newScannerPool(nil))
+	return pool.(*sync.Pool)
+}
+
+func newScannerPool(opts []pgxscan.Option) *sync.Pool {
+	return &sync.Pool{New: func() interface{} {
+		return &rowScanner{opts: opts}
+	}}
+}
+
+// rowScanner scans the rows of a result set, the rows sharing its field
+// descriptions reuse the converted field descriptions, column list and scan
+// plan of its Iterator.
+type rowScanner struct {
+	opts   []pgxscan.Option
+	fields []pgconn.FieldDescription
+	row    *rows
+	it     *pgxscan.Iterator
+}
+
+// reset prepares s to scan row, starting over when its columns changed. The
+// field descriptions are compared by value, pgx reuses their backing array
+// between the results of a connection.
+func (s *rowScanner) reset(row pgx.CollectableRow) {
+	fields := row.FieldDescriptions()
+	if s.it == nil || !sameFields(s.fields, fields) {
+		s.fields = append(s.fields[:0], fields...)
+		s.row = newRow(row)
+		s.it = pgxscan.NewIterator(s.row, s.opts...)
+		return
+	}
+	s.row.row = row
+}
+
+func sameFields(a, b []pgconn.FieldDescription) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// scanRowTo scans row into a T with a scanner of pool. A scanner that failed
+// is dropped, its Iterator keeps the error.
+func scanRowTo[T any](pool *sync.Pool, row pgx.CollectableRow) (T, error) {
+	s := pool.Get().(*rowScanner)
+	s.reset(row)
+	var dst T
+	if err := s.it.ScanRow(&dst); err != nil {
+		var zero T
+		return zero, err
+	}
+	pool.Put(s)
+	return dst, nil
+}
+
+// ForEachRow scans each row of src into a T and calls fn with it, like
+// pgx.ForEachRow does with its scan targets. src is closed and its command
+// tag returned. Rows are not aggregated, see pgxscan.Iterator.
+func ForEachRow[T any](src pgx.Rows, fn func(T) error, opts ...pgxscan.Option) (pgconn.CommandTag, error) {
+	defer src.Close()
+	it := pgxscan.NewIterator(newRows(src), opts...)
+	for it.Next() {
+		var dst T
+		if err := it.ScanRow(&dst); err != nil {
+			return pgconn.CommandTag{}, err
+		}
+		if err := fn(dst); err != nil {
+			return pgconn.CommandTag{}, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return src.CommandTag(), nil
+}
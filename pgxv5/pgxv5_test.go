@@ -0,0 +1,191 @@
+package pgxv5
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRows is an in memory pgx v5 Rows.
+type fakeRows struct {
+	fields []pgconn.FieldDescription
+	values [][]any
+	idx    int
+	closed bool
+	err    error
+}
+
+var _ pgx.Rows = (*fakeRows)(nil)
+
+func newFakeRows(cols []string, values ...[]any) *fakeRows {
+	fields := make([]pgconn.FieldDescription, len(cols))
+	for i, col := range cols {
+		fields[i] = pgconn.FieldDescription{Name: col}
+	}
+	return &fakeRows{fields: fields, values: values}
+}
+
+func (r *fakeRows) Close()                                       { r.closed = true }
+func (r *fakeRows) Err() error                                   { return r.err }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.NewCommandTag("SELECT 2") }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return r.fields }
+func (r *fakeRows) Values() ([]any, error)                       { return r.values[r.idx-1], nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	if r.closed || r.err != nil || r.idx >= len(r.values) {
+		r.closed = true
+		return false
+	}
+	r.idx++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	row := r.values[r.idx-1]
+	for i, d := range dest {
+		if d == nil {
+			continue
+		}
+		dv := reflect.ValueOf(d).Elem()
+		if row[i] == nil {
+			if dv.Kind() != reflect.Ptr {
+				r.err = pgx.ScanArgError{ColumnIndex: i, Err: fmt.Errorf("cannot scan NULL into %T", d)}
+				return r.err
+			}
+			dv.Set(reflect.Zero(dv.Type()))
+			continue
+		}
+		for dv.Kind() == reflect.Ptr {
+			dv.Set(reflect.New(dv.Type().Elem()))
+			dv = dv.Elem()
+		}
+		sv := reflect.ValueOf(row[i])
+		if !sv.Type().ConvertibleTo(dv.Type()) {
+			r.err = pgx.ScanArgError{ColumnIndex: i, Err: fmt.Errorf("cannot scan %T into %T", row[i], d)}
+			return r.err
+		}
+		dv.Set(sv.Convert(dv.Type()))
+	}
+	return nil
+}
+
+type (
+	address struct {
+		ID   int64
+		City string
+	}
+	user struct {
+		ID      int64
+		Name    string
+		Address *address `scan:"notate"`
+	}
+)
+
+func newUserRows() *fakeRows {
+	return newFakeRows([]string{"id", "name", "notate:address", "id", "city"},
+		[]any{int64(1), "user01", 0, int64(2), "city01"},
+		[]any{int64(2), "user02", 0, nil, nil},
+	)
+}
+
+var wantUsers = []user{
+	{ID: 1, Name: "user01", Address: &address{ID: 2, City: "city01"}},
+	{ID: 2, Name: "user02"},
+}
+
+func Test_CollectRows(t *testing.T) {
+	users, err := pgx.CollectRows(newUserRows(), RowToStructByTag[user])
+	require.NoError(t, err)
+	require.Equal(t, wantUsers, users)
+
+	ptrs, err := pgx.CollectRows(newUserRows(), RowToAddrOfStructByTag[user])
+	require.NoError(t, err)
+	require.Equal(t, []*user{&wantUsers[0], &wantUsers[1]}, ptrs)
+
+	src := newFakeRows([]string{"id", "name", "email"}, []any{int64(1), "user01", "user01@email.com"})
+	_, err = pgx.CollectRows(src, RowToStructByTag[user])
+	require.EqualError(t, err, `unable to find corresponding field to column "email" returned by query`)
+
+	src = newFakeRows([]string{"id", "name", "email"}, []any{int64(1), "user01", "user01@email.com"})
+	users, err = pgx.CollectRows(src, RowTo[user](pgxscan.MatchAllColumns(false)))
+	require.NoError(t, err)
+	require.Equal(t, []user{{ID: 1, Name: "user01"}}, users)
+}
+
+func Test_All(t *testing.T) {
+	users, err := All[user](newUserRows())
+	require.NoError(t, err)
+	require.Equal(t, wantUsers, users)
+
+	var dst []user
+	require.NoError(t, NewScanner(newUserRows()).Scan(&dst))
+	require.Equal(t, wantUsers, dst)
+}
+
+func Test_One(t *testing.T) {
+	_, err := One[user](newFakeRows([]string{"id", "name"}))
+	require.Equal(t, pgx.ErrNoRows, err)
+
+	_, err = One[user](newUserRows())
+	require.Equal(t, pgxscan.ErrTooManyRows, err)
+}
+
+func Test_ForEachRow(t *testing.T) {
+	var users []user
+	tag, err := ForEachRow(newUserRows(), func(u user) error {
+		users = append(users, u)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT 2", tag.String())
+	require.Equal(t, wantUsers, users)
+}
+
+func Test_ScanError(t *testing.T) {
+	type User struct {
+		ID   int64
+		Name int64
+	}
+	_, err := All[User](newFakeRows([]string{"id", "name"}, []any{int64(1), "user01"}))
+	var scanErr *pgxscan.ScanError
+	require.True(t, errors.As(err, &scanErr))
+	require.Equal(t, "name", scanErr.Column)
+	require.Equal(t, "Name", scanErr.Field)
+}
+
+func Test_RowTo_ReusesPlan(t *testing.T) {
+	src := newUserRows()
+	s := &rowScanner{}
+	require.True(t, src.Next())
+	s.reset(src)
+	it := s.it
+	require.True(t, src.Next())
+	s.reset(src)
+	require.Same(t, it, s.it)
+
+	// pgx reuses the backing array of the field descriptions of a connection,
+	// other columns in the same array start over
+	src.fields[1].Name = "email"
+	s.reset(src)
+	require.NotSame(t, it, s.it)
+}
+
+func Test_RowTo_ColumnsChange(t *testing.T) {
+	rowTo := RowTo[user](pgxscan.MatchAllColumns(false))
+	users, err := pgx.CollectRows(newUserRows(), rowTo)
+	require.NoError(t, err)
+	require.Equal(t, wantUsers, users)
+
+	src := newFakeRows([]string{"name", "id"}, []any{"user03", int64(3)})
+	users, err = pgx.CollectRows(src, rowTo)
+	require.NoError(t, err)
+	require.Equal(t, []user{{ID: 3, Name: "user03"}}, users)
+}
@@ -0,0 +1,100 @@
+package pgxv5
+
+import (
+	"errors"
+
+	pgconnv4 "github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	pgxv4 "github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v5"
+)
+
+// rows adapts the rows of pgx v5 to the pgx v4 interface used by pgxscan,
+// the values are still scanned by pgx v5.
+type rows struct {
+	row    pgx.CollectableRow
+	rows   pgx.Rows
+	fields []pgproto3.FieldDescription
+}
+
+var _ pgxv4.Rows = (*rows)(nil)
+
+func newRows(src pgx.Rows) *rows {
+	return &rows{row: src, rows: src}
+}
+
+// newRow adapts the current row of a CollectRows callback, it can only be
+// scanned.
+func newRow(src pgx.CollectableRow) *rows {
+	return &rows{row: src}
+}
+
+func (r *rows) Close() {
+	if r.rows != nil {
+		r.rows.Close()
+	}
+}
+
+func (r *rows) Err() error {
+	if r.rows != nil {
+		return r.rows.Err()
+	}
+	return nil
+}
+
+func (r *rows) CommandTag() pgconnv4.CommandTag {
+	if r.rows != nil {
+		return pgconnv4.CommandTag(r.rows.CommandTag().String())
+	}
+	return nil
+}
+
+func (r *rows) FieldDescriptions() []pgproto3.FieldDescription {
+	if r.fields == nil {
+		src := r.row.FieldDescriptions()
+		r.fields = make([]pgproto3.FieldDescription, len(src))
+		for i, f := range src {
+			r.fields[i] = pgproto3.FieldDescription{
+				Name:                 []byte(f.Name),
+				TableOID:             f.TableOID,
+				TableAttributeNumber: f.TableAttributeNumber,
+				DataTypeOID:          f.DataTypeOID,
+				DataTypeSize:         f.DataTypeSize,
+				TypeModifier:         f.TypeModifier,
+				Format:               f.Format,
+			}
+		}
+	}
+	return r.fields
+}
+
+func (r *rows) Next() bool {
+	return r.rows != nil && r.rows.Next()
+}
+
+// Scan scans the current row with pgx v5. Its scan errors are converted to the
+// pgx v4 ones, so they are reported as a pgxscan.ScanError.
+func (r *rows) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	var argErr pgx.ScanArgError
+	if errors.As(err, &argErr) {
+		return pgxv4.ScanArgError{ColumnIndex: argErr.ColumnIndex, Err: argErr.Err}
+	}
+	return err
+}
+
+func (r *rows) Values() ([]interface{}, error) {
+	return r.row.Values()
+}
+
+func (r *rows) RawValues() [][]byte {
+	return r.row.RawValues()
+}
+
+// convertErr returns the pgx v5 equivalent of the errors of pgx v4.
+func convertErr(err error) error {
+	if errors.Is(err, pgxv4.ErrNoRows) {
+		return pgx.ErrNoRows
+	}
+	return err
+}
@@ -0,0 +1,687 @@
+package pgxscan
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+)
+
+// scanPlan maps the columns of a result set to the fields of a struct type.
+// It is compiled once per struct type and column list, after that scanning
+// a row only has to collect the field addresses and hand them to pgx.
+type scanPlan struct {
+	typ  reflect.Type
+	cols []string
+	// fields holds the field index of each column, nil for the columns that
+	// are skipped.
+	fields [][]int
+	// paths holds the path of the field of each column, used to report
+	// scan errors.
+	paths []string
+	// unmapped lists the columns that are not notate columns and have no
+	// corresponding field.
+	unmapped []string
+	// types holds the type of the field of each column.
+	types []reflect.Type
+	// groups lists the nested pointer structs the columns are scanned into,
+	// parents before their children.
+	groups []nullableGroup
+	// colGroups holds the innermost group of each column, -1 when the
+	// column does not belong to a nested pointer struct.
+	colGroups []int
+	// nullable is set for the plans of the elements of many fields, whose
+	// struct is the first group.
+	nullable bool
+	// pk holds the columns of the fields with the pk tag option.
+	pk []int
+	// many lists the slice fields filled from the rows sharing the same pk,
+	// see aggregator.
+	many []manyField
+	// inline lists the unmapped columns collected by the map fields with
+	// the inline tag option.
+	inline []inlineColumn
+	// missing lists the fields the result set has no column for.
+	missing []missingField
+	// convs holds the converters of the field of each column, the one used
+	// is picked by the type of the column, see pickConverter.
+	convs [][]*converter
+	// converted is set when a column has converters.
+	converted bool
+}
+
+// missingField is a field of a plan the result set has no column for.
+type missingField struct {
+	column   string
+	path     string
+	index    []int
+	required bool
+	optional bool
+}
+
+// manyField is a slice of structs field with the many tag option, like
+// `db:"orders,many"`. Its element is scanned from the columns prefixed with
+// the field name, like "orders.id".
+type manyField struct {
+	index []int
+	plan  *scanPlan
+}
+
+// inlineColumn is a column collected by a map field with the inline tag
+// option, like `db:",inline"`, under key.
+type inlineColumn struct {
+	col   int
+	index []int
+	key   string
+}
+
+// presentOption names the column deciding whether a nested pointer struct
+// is allocated, like `db:"address,notate,present=id"`.
+const presentOption = "present"
+
+// nullableGroup is a nested pointer struct field, like `Address *Address`.
+// Its columns are scanned into nullable holders first and the pointer is
+// left nil when all of them, or its presence column, are NULL, which is what
+// a LEFT JOIN without a match returns.
+type nullableGroup struct {
+	index []int
+	typ   reflect.Type
+	// parent is the enclosing group, -1 for none.
+	parent int
+	// cols lists the columns of the group, including the ones of its
+	// children.
+	cols []int
+	// inline lists the inline columns collected by the inline fields of the
+	// group and its children, by index in scanPlan.inline.
+	inline []int
+	// presence is the column deciding whether the struct is present, -1 when
+	// any non NULL column does.
+	presence int
+}
+
+type planKey struct {
+	typ  reflect.Type
+	cols string
+}
+
+// scanPlan returns the plan scanning cols into t, compiling it if needed.
+// The plan is rejected when a column has no field and matchAllColumnsToStruct
+// is set, or when a required field, or any field that is not optional when
+// matchAllFields is set, has no column.
+func (m *Mapper) scanPlan(t reflect.Type, cols []string, matchAllColumnsToStruct, matchAllFields bool) (*scanPlan, error) {
+	if cols == nil {
+		return nil, ErrNoCols
+	}
+	key := planKey{typ: t, cols: strings.Join(cols, "\x00")}
+	var plan *scanPlan
+	if cached, ok := m.plans.Load(key); ok {
+		plan = cached.(*scanPlan)
+	} else {
+		compiled, err := m.compileScanPlan(t, cols)
+		if err != nil {
+			return nil, err
+		}
+		cached, _ = m.plans.LoadOrStore(key, compiled)
+		plan = cached.(*scanPlan)
+	}
+	if matchAllColumnsToStruct && len(plan.unmapped) != 0 {
+		return nil, unableToFindFieldError(plan.unmapped[0])
+	}
+	if err := plan.checkFields(matchAllFields); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// checkFields returns a *MissingFieldsError listing the required fields the
+// result set has no column for, and the ones that are not optional when
+// matchAllFields is set.
+func (p *scanPlan) checkFields(matchAllFields bool) error {
+	var err *MissingFieldsError
+	for _, f := range p.missing {
+		if f.required || (matchAllFields && !f.optional) {
+			if err == nil {
+				err = &MissingFieldsError{Type: p.typ}
+			}
+			err.Fields = append(err.Fields, f.path)
+			err.Columns = append(err.Columns, f.column)
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	return err
+}
+
+func (m *Mapper) compileScanPlan(t reflect.Type, cols []string) (*scanPlan, error) {
+	return m.compilePlan(t, cols, "", false)
+}
+
+// compilePlan compiles the plan of the columns starting with prefix. The
+// plan of the element of a many field is nullable: its struct is absent when
+// its columns are NULL.
+func (m *Mapper) compilePlan(t reflect.Type, cols []string, prefix string, nullable bool) (*scanPlan, error) {
+	cm, err := m.columnMap(t)
+	if err != nil {
+		return nil, err
+	}
+	plan := &scanPlan{
+		typ:       t,
+		cols:      cols,
+		fields:    make([][]int, len(cols)),
+		paths:     make([]string, len(cols)),
+		types:     make([]reflect.Type, len(cols)),
+		colGroups: make([]int, len(cols)),
+		convs:     make([][]*converter, len(cols)),
+		nullable:  nullable,
+	}
+	var many []string
+	seen := map[string]bool{}
+	for idx, col := range cols {
+		if strings.HasPrefix(col, m.notatePrefix()) || !strings.HasPrefix(col, prefix) {
+			// notated columns are always skipped, the columns of the parents
+			// are scanned by their own plan
+			continue
+		}
+		name := col[len(prefix):]
+		seen[name] = true
+		if key, ok := manyFieldOf(cm, name); ok {
+			if !containsString(many, key) {
+				many = append(many, key)
+			}
+			continue
+		}
+		data, ok := cm[name]
+		switch {
+		case !ok || data.Many || data.Inline:
+			if index, key, ok := inlineFieldOf(cm, name); ok {
+				plan.inline = append(plan.inline, inlineColumn{col: idx, index: index, key: key})
+			} else {
+				plan.unmapped = append(plan.unmapped, col)
+			}
+		default:
+			plan.fields[idx] = data.FieldIndex
+			plan.paths[idx] = fieldPath(t, data.FieldIndex)
+			plan.types[idx] = data.GoType
+			if data.JSON {
+				plan.convs[idx] = []*converter{m.jsonConverter(data.GoType)}
+			} else if plan.convs[idx], err = m.fieldConverters(t.FieldByIndex(data.FieldIndex), data.GoType); err != nil {
+				return nil, err
+			}
+			convs := plan.convs[idx]
+			plan.converted = plan.converted || len(convs) != 0
+			if data.PrimaryKey {
+				plan.pk = append(plan.pk, idx)
+			}
+		}
+	}
+	for _, key := range many {
+		data := cm[key]
+		if len(plan.pk) == 0 {
+			return nil, fmt.Errorf("many field %s of %v requires a pk column to group rows", fieldPath(t, data.FieldIndex), t)
+		}
+		elem := data.GoType.Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		child, err := m.compilePlan(elem, cols, prefix+key+".", true)
+		if err != nil {
+			return nil, err
+		}
+		plan.many = append(plan.many, manyField{index: data.FieldIndex, plan: child})
+		plan.unmapped = append(plan.unmapped, child.unmapped...)
+		for _, f := range child.missing {
+			f.path = fieldPath(t, data.FieldIndex) + "." + f.path
+			f.index = append(append([]int{}, data.FieldIndex...), f.index...)
+			plan.missing = append(plan.missing, f)
+		}
+	}
+	for key, data := range cm {
+		if seen[key] || data.Inline || (data.Many && containsString(many, key)) {
+			continue
+		}
+		plan.missing = append(plan.missing, missingField{
+			column:   prefix + key,
+			path:     fieldPath(t, data.FieldIndex),
+			index:    data.FieldIndex,
+			required: data.Required,
+			optional: data.Optional,
+		})
+	}
+	sort.Slice(plan.missing, func(i, j int) bool {
+		return lessIndex(plan.missing[i].index, plan.missing[j].index)
+	})
+	if err := m.compileNullableGroups(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// manyFieldOf returns the key of the many field of cm the column name
+// belongs to, like "orders" for "orders.id".
+func manyFieldOf(cm sqlmaper.ColumnMap, name string) (string, bool) {
+	var found string
+	for key, data := range cm {
+		if data.Many && strings.HasPrefix(name, key+".") && len(key) > len(found) {
+			found = key
+		}
+	}
+	return found, found != ""
+}
+
+// inlineFieldOf returns the index of the inline map field of cm collecting
+// the unmapped column name and the key of the column in the map. The inline
+// field of a nested struct collects the columns prefixed with its path, like
+// "address.zip", before the inline field of its parents.
+func inlineFieldOf(cm sqlmaper.ColumnMap, name string) (index []int, key string, ok bool) {
+	found := -1
+	for field, data := range cm {
+		if !data.Inline {
+			continue
+		}
+		parent := ""
+		if dot := strings.LastIndexByte(field, '.'); dot >= 0 {
+			parent = field[:dot+1]
+		}
+		if strings.HasPrefix(name, parent) && len(parent) > found {
+			found, index, key = len(parent), data.FieldIndex, name[len(parent):]
+		}
+	}
+	return index, key, found >= 0
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// compileNullableGroups finds the nested pointer structs along the fields of
+// the mapped and inline columns. The struct of a nullable plan is a group
+// itself.
+func (m *Mapper) compileNullableGroups(plan *scanPlan) error {
+	groups := map[string]int{}
+	root := -1
+	if plan.nullable {
+		root = 0
+		presence := -1
+		if len(plan.pk) != 0 {
+			presence = plan.pk[0]
+		}
+		plan.groups = append(plan.groups, nullableGroup{typ: plan.typ, parent: -1, presence: presence})
+	}
+	// walk calls add with the groups along the fields of index, creating the
+	// missing ones, and returns the innermost one
+	walk := func(index []int, add func(g int)) int {
+		parent := root
+		if root >= 0 {
+			add(root)
+		}
+		t := plan.typ
+		for depth := 0; depth < len(index)-1; depth++ {
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			f := t.Field(index[depth])
+			t = f.Type
+			if f.Type.Kind() != reflect.Ptr || f.Type.Elem().Kind() != reflect.Struct {
+				continue
+			}
+			key := fmt.Sprint(index[:depth+1])
+			g, ok := groups[key]
+			if !ok {
+				g = len(plan.groups)
+				groups[key] = g
+				plan.groups = append(plan.groups, nullableGroup{
+					index:    append([]int{}, index[:depth+1]...),
+					typ:      f.Type,
+					parent:   parent,
+					presence: -1,
+				})
+			}
+			add(g)
+			parent = g
+		}
+		return parent
+	}
+	for idx, index := range plan.fields {
+		plan.colGroups[idx] = -1
+		if index != nil {
+			plan.colGroups[idx] = walk(index, func(g int) {
+				plan.groups[g].cols = append(plan.groups[g].cols, idx)
+			})
+		}
+	}
+	for i, c := range plan.inline {
+		walk(c.index, func(g int) {
+			plan.groups[g].inline = append(plan.groups[g].inline, i)
+		})
+	}
+
+	for g := range plan.groups {
+		group := &plan.groups[g]
+		if group.index == nil {
+			continue
+		}
+		f := plan.typ.FieldByIndex(group.index)
+		name, ok := m.mapper.FieldOptions(f).Value(presentOption)
+		if !ok {
+			continue
+		}
+		for _, idx := range group.cols {
+			if col := plan.cols[idx]; col == name || strings.HasSuffix(col, "."+name) {
+				group.presence = idx
+				break
+			}
+		}
+		if group.presence < 0 {
+			return fmt.Errorf(`presence column "%s" of field %s is not returned by query`, name, fieldPath(plan.typ, group.index))
+		}
+	}
+	return nil
+}
+
+// scan scans the current row straight into the fields of dst, which must be
+// an addressable struct of the type the plan was compiled for. The columns of
+// nested pointer structs go through nullable holders, see nullableGroup, and
+// each many field gets the element of the row, if any.
+func (p *scanPlan) scan(scan scannerFunc, dst reflect.Value) error {
+	return p.scanFields(scan, dst, nil)
+}
+
+// scanFields is like scan, the values of the inline columns being decoded by
+// the type of their field description, see recordValueType, which also
+// picks the converters of the columns. They are decoded by pgx, and only the
+// converters registered for Go types are used, when fields is nil.
+func (p *scanPlan) scanFields(scan scannerFunc, dst reflect.Value, fields []pgproto3.FieldDescription) error {
+	targets := make([]interface{}, len(p.fields))
+	if len(p.groups) == 0 && len(p.many) == 0 && len(p.inline) == 0 && !p.converted {
+		for idx, index := range p.fields {
+			if index != nil {
+				targets[idx] = sqlmaper.FieldByIndex(dst, index).Addr().Interface()
+			}
+		}
+		if err := scan(targets...); err != nil {
+			return p.scanError(err)
+		}
+		return nil
+	}
+	assign := p.bind(dst, targets, fields)
+	if err := scan(targets...); err != nil {
+		return p.scanError(err)
+	}
+	_, err := assign()
+	return err
+}
+
+// bind sets the targets of the columns of p and returns the func assigning
+// the scanned values to dst, which reports whether dst is present.
+func (p *scanPlan) bind(dst reflect.Value, targets []interface{}, fields []pgproto3.FieldDescription) func() (bool, error) {
+	var holders []reflect.Value
+	if len(p.groups) != 0 {
+		holders = make([]reflect.Value, len(p.fields))
+	}
+	var (
+		convs       []*converter
+		convHolders []reflect.Value
+	)
+	if p.converted {
+		convs = make([]*converter, len(p.fields))
+		convHolders = make([]reflect.Value, len(p.fields))
+	}
+	for idx, index := range p.fields {
+		if p.converted && index != nil {
+			var oid uint32
+			if idx < len(fields) {
+				oid = fields[idx].DataTypeOID
+			}
+			convs[idx] = pickConverter(p.convs[idx], oid)
+		}
+		switch {
+		case index == nil:
+		case convs != nil && convs[idx] != nil:
+			convHolders[idx] = convs[idx].newHolder()
+			targets[idx] = convHolders[idx].Interface()
+			if holders != nil && p.colGroups[idx] >= 0 {
+				holders[idx] = convHolders[idx]
+			}
+		case holders != nil && p.colGroups[idx] >= 0:
+			holders[idx] = reflect.New(reflect.PtrTo(p.types[idx]))
+			targets[idx] = holders[idx].Interface()
+		default:
+			targets[idx] = sqlmaper.FieldByIndex(dst, index).Addr().Interface()
+		}
+	}
+	inline := make([]reflect.Value, len(p.inline))
+	oids := make([]uint32, len(p.inline))
+	for i, c := range p.inline {
+		if c.col < len(fields) {
+			oids[i] = fields[c.col].DataTypeOID
+		}
+		inline[i] = newRecordHolder(recordValueType(oids[i]))
+		targets[c.col] = inline[i].Interface()
+	}
+	elems := make([]reflect.Value, len(p.many))
+	assignElems := make([]func() (bool, error), len(p.many))
+	for i, many := range p.many {
+		elems[i] = reflect.New(many.plan.typ)
+		assignElems[i] = many.plan.bind(elems[i].Elem(), targets, fields)
+	}
+	return func() (bool, error) {
+		present := true
+		if holders != nil {
+			var err error
+			if present, err = p.assignNullable(dst, holders, inline, convs); err != nil {
+				return false, err
+			}
+		}
+		for idx, conv := range convs {
+			if conv == nil || (holders != nil && p.colGroups[idx] >= 0) {
+				continue
+			}
+			if err := p.assignConverted(dst, idx, conv, convHolders[idx]); err != nil {
+				return false, err
+			}
+		}
+		if err := p.assignInline(dst, inline, oids); err != nil {
+			return false, err
+		}
+		for i, many := range p.many {
+			f := sqlmaper.FieldByIndex(dst, many.index)
+			f.Set(reflect.Zero(f.Type()))
+			ok, err := assignElems[i]()
+			if err != nil {
+				return false, err
+			}
+			if ok && present {
+				sqlmaper.AppendSliceElement(f, elems[i])
+			}
+		}
+		return present, nil
+	}
+}
+
+// assignInline sets the inline map fields of dst to new maps of the values
+// of their columns. The inline fields of absent nested pointer structs are
+// left out.
+func (p *scanPlan) assignInline(dst reflect.Value, holders []reflect.Value, oids []uint32) error {
+	var reset map[string]bool
+	for i, c := range p.inline {
+		f, ok := sqlmaper.SafeGetFieldByIndex(dst, c.index)
+		if !ok {
+			continue
+		}
+		if path := fmt.Sprint(c.index); !reset[path] {
+			if reset == nil {
+				reset = map[string]bool{}
+			}
+			reset[path] = true
+			f.Set(reflect.MakeMap(f.Type()))
+		}
+		value, err := recordValue(holders[i], isJSONColumn(oids[i]))
+		if err != nil {
+			return &ScanError{
+				Column:      p.cols[c.col],
+				Ordinal:     c.col,
+				DataTypeOID: oids[i],
+				Field:       fieldPath(p.typ, c.index),
+				GoType:      f.Type().Elem(),
+				Err:         err,
+			}
+		}
+		if value == nil {
+			f.SetMapIndex(reflect.ValueOf(c.key).Convert(f.Type().Key()), reflect.Zero(f.Type().Elem()))
+		} else {
+			f.SetMapIndex(reflect.ValueOf(c.key).Convert(f.Type().Key()), reflect.ValueOf(value))
+		}
+	}
+	return nil
+}
+
+// assignNullable sets the nested pointer structs of dst from the holders
+// of their columns, converted by convs if any. A struct is present when one of
+// its mapped or inline columns is not NULL, the inline holders being assigned
+// later by assignInline. A NULL column leaves its field with its zero value.
+// It reports whether dst itself is present.
+func (p *scanPlan) assignNullable(dst reflect.Value, holders, inline []reflect.Value, convs []*converter) (bool, error) {
+	present := make([]bool, len(p.groups))
+	for g, group := range p.groups {
+		if group.parent >= 0 && !present[group.parent] {
+			continue
+		}
+		if group.presence >= 0 {
+			present[g] = !holders[group.presence].Elem().IsNil()
+		} else {
+			present[g] = anyNotNull(holders, group.cols) || anyNotNull(inline, group.inline)
+		}
+		f := sqlmaper.FieldByIndex(dst, group.index)
+		switch {
+		case !present[g]:
+			f.Set(reflect.Zero(group.typ))
+		case group.index != nil && f.IsNil():
+			// the struct may have no mapped column to allocate it
+			f.Set(reflect.New(group.typ.Elem()))
+		}
+	}
+	for idx, g := range p.colGroups {
+		if g < 0 || !present[g] {
+			continue
+		}
+		if convs != nil && convs[idx] != nil {
+			if err := p.assignConverted(dst, idx, convs[idx], holders[idx]); err != nil {
+				return false, err
+			}
+			continue
+		}
+		f := sqlmaper.FieldByIndex(dst, p.fields[idx])
+		if v := holders[idx].Elem(); v.IsNil() {
+			if err := p.assignNull(f, idx); err != nil {
+				return false, err
+			}
+		} else {
+			f.Set(v.Elem())
+		}
+	}
+	return !p.nullable || present[0], nil
+}
+
+// anyNotNull reports whether one of the holders at indexes is not NULL.
+func anyNotNull(holders []reflect.Value, indexes []int) bool {
+	for _, i := range indexes {
+		if !holders[i].Elem().IsNil() {
+			return true
+		}
+	}
+	return false
+}
+
+// assignNull sets f, the field of column idx, to NULL. A sql.Scanner, like the
+// pgtype types, scans nil to keep its own NULL state, other fields get their
+// zero value.
+func (p *scanPlan) assignNull(f reflect.Value, idx int) error {
+	s, ok := f.Addr().Interface().(sql.Scanner)
+	if !ok || f.Kind() == reflect.Ptr {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+	if err := s.Scan(nil); err != nil {
+		return &ScanError{
+			Column:  p.cols[idx],
+			Ordinal: idx,
+			Field:   p.paths[idx],
+			GoType:  p.types[idx],
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// assignConverted sets the field of column idx of dst to the value scanned
+// into holder, converted by conv.
+func (p *scanPlan) assignConverted(dst reflect.Value, idx int, conv *converter, holder reflect.Value) error {
+	if err := conv.assign(sqlmaper.FieldByIndex(dst, p.fields[idx]), holder); err != nil {
+		return &ScanError{
+			Column:      p.cols[idx],
+			Ordinal:     idx,
+			DataTypeOID: conv.oid,
+			Field:       p.paths[idx],
+			GoType:      p.types[idx],
+			Err:         err,
+		}
+	}
+	return nil
+}
+
+// scanError identifies the offending field in case types do not match, very
+// useful when using this library
+func (p *scanPlan) scanError(err error) error {
+	var argErr pgx.ScanArgError
+	if !errors.As(err, &argErr) || argErr.ColumnIndex < 0 || argErr.ColumnIndex >= len(p.cols) {
+		return err
+	}
+	idx := argErr.ColumnIndex
+	scanErr := &ScanError{
+		Column:  p.cols[idx],
+		Ordinal: idx,
+		Err:     argErr.Err,
+	}
+	scanErr.Field, scanErr.GoType = p.field(idx)
+	return scanErr
+}
+
+// field returns the path and type of the field column idx is scanned into,
+// looking into the many fields.
+func (p *scanPlan) field(idx int) (string, reflect.Type) {
+	if p.fields[idx] != nil {
+		return p.paths[idx], p.types[idx]
+	}
+	for _, many := range p.many {
+		if path, typ := many.plan.field(idx); path != "" {
+			return fieldPath(p.typ, many.index) + "." + path, typ
+		}
+	}
+	return "", nil
+}
+
+// fieldPath returns the dotted names of the fields of t along fieldIndex.
+func fieldPath(t reflect.Type, fieldIndex []int) string {
+	names := make([]string, len(fieldIndex))
+	for i, x := range fieldIndex {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		f := t.Field(x)
+		names[i] = f.Name
+		t = f.Type
+	}
+	return strings.Join(names, ".")
+}
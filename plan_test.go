@@ -0,0 +1,244 @@
+package pgxscan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Mapper_scanPlan(t *testing.T) {
+	type (
+		Address struct {
+			ID   int64
+			City string
+		}
+		User struct {
+			ID      int64
+			Name    string
+			Address *Address `scan:"notate"`
+		}
+	)
+	cols := []string{"id", "name", "notate:address", "address.id", "address.city", "email"}
+	typ := reflect.TypeOf(User{})
+
+	plan, err := DefaultMapper.scanPlan(typ, cols, false, false)
+	require.NoError(t, err)
+	require.Equal(t, [][]int{{0}, {1}, nil, {2, 0}, {2, 1}, nil}, plan.fields)
+	require.Equal(t, []string{"email"}, plan.unmapped)
+
+	cached, err := DefaultMapper.scanPlan(typ, cols, false, false)
+	require.NoError(t, err)
+	require.True(t, plan == cached, "plan should be cached")
+
+	_, err = DefaultMapper.scanPlan(typ, cols, true, false)
+	require.EqualError(t, err, `unable to find corresponding field to column "email" returned by query`)
+
+	_, err = DefaultMapper.scanPlan(typ, nil, true, false)
+	require.Equal(t, ErrNoCols, err)
+}
+
+func Test_scanPlan_scan(t *testing.T) {
+	type (
+		Address struct {
+			ID   int64
+			City string
+		}
+		User struct {
+			ID      int64
+			Name    string
+			Address *Address `scan:"notate"`
+		}
+	)
+	src := newFakeRows([]string{"id", "name", "notate:address", "id", "city"},
+		[]interface{}{int64(1), "user01", 0, int64(2), "city01"},
+	)
+	var users []User
+	require.NoError(t, NewScanner(src).Scan(&users))
+	require.Equal(t, []User{{ID: 1, Name: "user01", Address: &Address{ID: 2, City: "city01"}}}, users)
+}
+
+func Test_scanPlan_scan_NullPointerStruct(t *testing.T) {
+	type (
+		Geo struct {
+			Lat float64
+			Lng float64
+		}
+		Address struct {
+			ID   int64
+			City string
+			Geo  *Geo `scan:"notate"`
+		}
+		User struct {
+			ID      int64
+			Name    string
+			Address *Address `scan:"notate"`
+		}
+	)
+	cols := []string{"id", "name", "address.id", "address.city", "address.geo.lat", "address.geo.lng"}
+	src := newFakeRows(cols,
+		[]interface{}{int64(1), "user01", int64(2), "city01", 1.5, 2.5},
+		[]interface{}{int64(2), "user02", int64(3), nil, nil, nil},
+		[]interface{}{int64(3), "user03", nil, nil, nil, nil},
+	)
+	var users []User
+	require.NoError(t, NewScanner(src).Scan(&users))
+	require.Equal(t, []User{
+		{ID: 1, Name: "user01", Address: &Address{ID: 2, City: "city01", Geo: &Geo{Lat: 1.5, Lng: 2.5}}},
+		{ID: 2, Name: "user02", Address: &Address{ID: 3}},
+		{ID: 3, Name: "user03"},
+	}, users)
+
+	// a reused destination is reset
+	user := User{Address: &Address{ID: 9}}
+	src = newFakeRows(cols, []interface{}{int64(3), "user03", nil, nil, nil, nil})
+	require.NoError(t, NewScanner(src).Scan(&user))
+	require.Equal(t, User{ID: 3, Name: "user03"}, user)
+}
+
+func Test_scanPlan_scan_NullScannerInPointerStruct(t *testing.T) {
+	type (
+		Address struct {
+			ID   int64
+			City pgtype.Text
+		}
+		User struct {
+			ID      int64
+			City    pgtype.Text
+			Address *Address `scan:"notate"`
+		}
+	)
+	src := newFakeRows([]string{"id", "city", "address.id", "address.city"},
+		[]interface{}{int64(1), nil, int64(2), nil},
+	)
+	var user User
+	require.NoError(t, NewScanner(src).Scan(&user))
+	require.Equal(t, pgtype.Null, user.City.Status)
+	require.NotNil(t, user.Address)
+	require.Equal(t, pgtype.Null, user.Address.City.Status)
+}
+
+func Test_scanPlan_scan_PresenceColumn(t *testing.T) {
+	type (
+		Address struct {
+			ID   *int64
+			City string
+		}
+		User struct {
+			ID      int64
+			Address *Address `db:"address,notate,present=id"`
+		}
+	)
+	src := newFakeRows([]string{"id", "address.id", "address.city"},
+		[]interface{}{int64(1), nil, "city01"},
+		[]interface{}{int64(2), int64(3), nil},
+	)
+	var users []User
+	require.NoError(t, NewScanner(src).Scan(&users))
+	require.Equal(t, []User{
+		{ID: 1},
+		{ID: 2, Address: &Address{ID: int64ToPtr(3)}},
+	}, users)
+
+	src = newFakeRows([]string{"id", "address.city"}, []interface{}{int64(1), "city01"})
+	err := NewScanner(src).Scan(&users)
+	require.EqualError(t, err, `presence column "id" of field Address is not returned by query`)
+}
+
+func Benchmark_rows_ScanSliceOfStructs(b *testing.B) {
+	type User struct {
+		ID    int64
+		Name  string
+		Email string
+	}
+	values := make([][]interface{}, 1000)
+	for i := range values {
+		values[i] = []interface{}{int64(i), "user", "user@email.com"}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dst []User
+		if err := NewScanner(newFakeRows([]string{"id", "name", "email"}, values...)).Scan(&dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Test_scanPlan_scan_Inline(t *testing.T) {
+	type (
+		Address struct {
+			City  string
+			Extra map[string]interface{} `db:",inline"`
+		}
+		User struct {
+			ID      int64
+			Address *Address               `db:"address,notate"`
+			Extra   map[string]interface{} `db:",inline"`
+		}
+	)
+	src := newFakeRows([]string{"id", "email", "notate:address", "city", "zip", "notate:", "active"},
+		[]interface{}{int64(1), "user01@email.com", 0, "city01", "01000", 0, true},
+		[]interface{}{int64(2), nil, 0, nil, nil, 0, false},
+	)
+	var users []User
+	require.NoError(t, NewScanner(src).Scan(&users))
+	require.Equal(t, []User{
+		{
+			ID:      1,
+			Address: &Address{City: "city01", Extra: map[string]interface{}{"zip": "01000"}},
+			Extra:   map[string]interface{}{"email": "user01@email.com", "active": true},
+		},
+		{ID: 2, Extra: map[string]interface{}{"email": nil, "active": false}},
+	}, users)
+
+	// a reused destination does not keep the columns of the previous rows
+	src = newFakeRows([]string{"id", "email"}, []interface{}{int64(1), "user01@email.com"})
+	user := User{Extra: map[string]interface{}{"stale": true}}
+	require.NoError(t, ScanOne(src, &user))
+	require.Equal(t, map[string]interface{}{"email": "user01@email.com"}, user.Extra)
+
+	cols, err := SelectColumns(User{}, "")
+	require.NoError(t, err)
+	require.Equal(t, `"id", 0 AS "notate:address", "city"`, cols)
+}
+
+func Test_scanPlan_scan_InlineInPointerStruct(t *testing.T) {
+	type (
+		Address struct {
+			City  string
+			Extra map[string]interface{} `db:",inline"`
+		}
+		User struct {
+			ID      int64
+			Address *Address `db:"address,notate"`
+		}
+		Account struct {
+			ID    int64
+			Owner *struct {
+				Extra map[string]interface{} `db:",inline"`
+			} `db:"owner,notate"`
+		}
+	)
+	// the inline columns of a pointer struct make it present, even when its
+	// mapped columns are NULL
+	src := newFakeRows([]string{"id", "notate:address", "city", "zip"},
+		[]interface{}{int64(1), 0, nil, "01000"},
+		[]interface{}{int64(2), 0, nil, nil},
+	)
+	var users []User
+	require.NoError(t, NewScanner(src).Scan(&users))
+	require.Equal(t, []User{
+		{ID: 1, Address: &Address{Extra: map[string]interface{}{"zip": "01000"}}},
+		{ID: 2},
+	}, users)
+
+	// or when it has no mapped column
+	src = newFakeRows([]string{"id", "notate:owner", "name"}, []interface{}{int64(1), 0, "user01"})
+	var account Account
+	require.NoError(t, ScanOne(src, &account))
+	require.NotNil(t, account.Owner)
+	require.Equal(t, map[string]interface{}{"name": "user01"}, account.Owner.Extra)
+}
@@ -0,0 +1,63 @@
+package pgxscan
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Querier is the subset of a pgx connection needed to run a query.
+// *pgx.Conn, pgx.Tx, *pgxpool.Pool and *pgxpool.Conn all satisfy it.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// Select runs sql on q and scans every returned row into dst, which is
+// usually a pointer to a slice of structs.
+//
+// Any Option found in args is applied to the scanner instead of being sent
+// as a query argument:
+//
+//	var users []User
+//	err := pgxscan.Select(ctx, conn, &users, `SELECT * FROM users WHERE active = $1`, true, pgxscan.ErrNoRowsQuery(false))
+func Select(ctx context.Context, q Querier, dst interface{}, sql string, args ...interface{}) error {
+	args, opts := splitOptions(args)
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	return NewScanner(rows, opts...).Scan(dst)
+}
+
+// Get runs sql on q and scans the single returned row into dst, which is
+// usually a pointer to a struct or to a builtin type. Options in args are
+// handled as they are by Select.
+//
+// Unlike QueryRow, the query runs through Query, so the columns are known and
+// structs can be scanned. pgx.ErrNoRows is returned when there is no row and
+// ErrTooManyRows when there is more than one.
+//
+//	var user User
+//	err := pgxscan.Get(ctx, conn, &user, `SELECT * FROM users WHERE id = $1`, 1)
+func Get(ctx context.Context, q Querier, dst interface{}, sql string, args ...interface{}) error {
+	args, opts := splitOptions(args)
+	res, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	return (&rows{rows: res, cfg: newConfig(opts...)}).scanOne(dst)
+}
+
+// splitOptions separates the scanner options from the query arguments.
+func splitOptions(args []interface{}) ([]interface{}, []Option) {
+	var opts []Option
+	queryArgs := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		if opt, ok := arg.(Option); ok {
+			opts = append(opts, opt)
+			continue
+		}
+		queryArgs = append(queryArgs, arg)
+	}
+	return queryArgs, opts
+}
@@ -0,0 +1,58 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Select(t *testing.T) {
+	var users []user
+	err := pgxscan.Select(context.Background(), testDB, &users, `SELECT "id", "name", "email" FROM "users" WHERE "id" < $1 ORDER BY "id"`, 3)
+	require.NoError(t, err)
+	require.Equal(t, []user{
+		{ID: 1, Name: "user01", Email: "user01@email.com"},
+		{ID: 2, Name: "user02", Email: "user02@email.com"},
+	}, users)
+}
+
+func Test_Select_NoRows(t *testing.T) {
+	var users []user
+	err := pgxscan.Select(context.Background(), testDB, &users, `SELECT "id", "name", "email" FROM "users" WHERE "id" < $1`, 0, pgxscan.ErrNoRowsQuery(false))
+	require.NoError(t, err)
+	require.Empty(t, users)
+}
+
+func Test_Get(t *testing.T) {
+	var u user
+	err := pgxscan.Get(context.Background(), newTestDB(t), &u, `SELECT "id", "name", "email" FROM "users" WHERE "id" = $1`, 1)
+	require.NoError(t, err)
+	require.Equal(t, user{ID: 1, Name: "user01", Email: "user01@email.com"}, u)
+
+	var count int
+	err = pgxscan.Get(context.Background(), testDB, &count, `SELECT COUNT(*) FROM "users"`)
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+}
+
+func Test_Get_InTx(t *testing.T) {
+	tx, err := testDB.Begin(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+
+	var u user
+	err = pgxscan.Get(context.Background(), tx, &u, `SELECT "id", "name", "email" FROM "users" WHERE "id" = $1`, 0)
+	require.Equal(t, pgx.ErrNoRows, err)
+}
+
+func Test_Get_WantErr_TooManyRows(t *testing.T) {
+	var u user
+	err := pgxscan.Get(context.Background(), testDB, &u, `SELECT "id", "name", "email" FROM "users" WHERE "id" < $1`, 3)
+	require.Equal(t, pgxscan.ErrTooManyRows, err)
+}
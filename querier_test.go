@@ -0,0 +1,70 @@
+package pgxscan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQuerier struct {
+	rows *fakeRows
+	err  error
+	sql  string
+	args []interface{}
+}
+
+func (q *fakeQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	q.sql, q.args = sql, args
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if q.err != nil {
+		return nil, q.err
+	}
+	return q.rows, nil
+}
+
+func Test_Select(t *testing.T) {
+	q := &fakeQuerier{rows: newGenericUserRows()}
+
+	var users []genericUser
+	err := Select(context.Background(), q, &users, `SELECT id, name FROM users WHERE id < $1`, 3)
+	require.NoError(t, err)
+	require.Equal(t, []genericUser{{ID: 1, Name: "user01"}, {ID: 2, Name: "user02"}}, users)
+	require.Equal(t, `SELECT id, name FROM users WHERE id < $1`, q.sql)
+	require.Equal(t, []interface{}{3}, q.args)
+}
+
+func Test_Select_WithOptions(t *testing.T) {
+	q := &fakeQuerier{rows: newFakeRows([]string{"id", "name"})}
+
+	var users []genericUser
+	err := Select(context.Background(), q, &users, `SELECT id, name FROM users WHERE id < $1`, 3, ErrNoRowsQuery(false))
+	require.NoError(t, err)
+	require.Empty(t, users)
+	require.Equal(t, []interface{}{3}, q.args)
+}
+
+func Test_Get(t *testing.T) {
+	q := &fakeQuerier{rows: newFakeRows([]string{"id", "name", "email"},
+		[]interface{}{int64(1), "user01", "user01@email.com"},
+	)}
+
+	var user genericUser
+	err := Get(context.Background(), q, &user, `SELECT * FROM users WHERE id = $1`, 1, MatchAllColumns(false))
+	require.NoError(t, err)
+	require.Equal(t, genericUser{ID: 1, Name: "user01"}, user)
+	require.Equal(t, []interface{}{1}, q.args)
+}
+
+func Test_Get_WantErr_Query(t *testing.T) {
+	queryErr := errors.New("syntax error")
+	q := &fakeQuerier{err: queryErr}
+
+	var user genericUser
+	err := Get(context.Background(), q, &user, `SELEC * FROM users`)
+	require.Equal(t, queryErr, err)
+}
@@ -0,0 +1,270 @@
+package pgxscan
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+)
+
+// NestedMaps sets whether the notated columns scanned into a
+// map[string]interface{} are nested maps, like
+// {"address": {"city": "city01"}}, rather than dotted keys like
+// {"address.city": "city01"}.
+func NestedMaps(b bool) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.NestedMaps = b
+	})
+}
+
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// isRecord reports whether t is scanned as a record, a map of the columns of
+// a row by name like map[string]interface{}, rather than a map of keys to
+// values.
+func isRecord(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem() == interfaceType
+}
+
+// isRecordDest reports whether i is a pointer to a record or to a slice of
+// records.
+func isRecordDest(i interface{}) bool {
+	t := reflect.TypeOf(i)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return isRecord(t) || (t.Kind() == reflect.Slice && isRecord(t.Elem()))
+}
+
+// recordPlan scans the rows of a result set into records.
+type recordPlan struct {
+	// keys holds the key path of each column, nil for the notate columns
+	// which are skipped.
+	keys  [][]string
+	types []reflect.Type
+	json  []bool
+	oids  []uint32
+}
+
+// recordPlan returns the plan scanning the current result set into records.
+// Columns are named as described in GetColumnNames, the notate columns being
+// left out, and a column sharing the name of a previous one replaces it.
+func (r *rows) recordPlan() (*recordPlan, error) {
+	cols, err := r.cfg.Mapper.columnNames(r.rows)
+	if err != nil {
+		return nil, err
+	}
+	fields := r.rows.FieldDescriptions()
+	prefix := r.cfg.Mapper.notatePrefix()
+	plan := &recordPlan{
+		keys:  make([][]string, len(cols)),
+		types: make([]reflect.Type, len(cols)),
+		json:  make([]bool, len(cols)),
+		oids:  make([]uint32, len(cols)),
+	}
+	for i, col := range cols {
+		if strings.HasPrefix(col, prefix) {
+			continue
+		}
+		if i < len(fields) {
+			plan.oids[i] = fields[i].DataTypeOID
+		}
+		if r.cfg.NestedMaps {
+			plan.keys[i] = strings.Split(col, ".")
+		} else {
+			plan.keys[i] = []string{col}
+		}
+		plan.types[i] = recordValueType(plan.oids[i])
+		plan.json[i] = isJSONColumn(plan.oids[i])
+	}
+	return plan, nil
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// recordValueType returns the Go type of the values of the postgres type oid
+// in a record: integers are int64, floating point numbers float64, numeric
+// values and uuids are strings to keep their precision and format, dates and
+// timestamps are time.Time and json is decoded. Other types get the value
+// pgx decodes them to.
+func recordValueType(oid uint32) reflect.Type {
+	switch oid {
+	case pgtype.BoolOID:
+		return reflect.TypeOf(false)
+	case pgtype.Int2OID, pgtype.Int4OID, pgtype.Int8OID:
+		return reflect.TypeOf(int64(0))
+	case pgtype.Float4OID, pgtype.Float8OID:
+		return reflect.TypeOf(float64(0))
+	case pgtype.NumericOID, pgtype.TextOID, pgtype.VarcharOID, pgtype.BPCharOID, pgtype.NameOID, pgtype.UUIDOID:
+		return reflect.TypeOf("")
+	case pgtype.DateOID, pgtype.TimestampOID, pgtype.TimestamptzOID:
+		return timeType
+	case pgtype.ByteaOID, pgtype.JSONOID, pgtype.JSONBOID:
+		return bytesType
+	}
+	return interfaceType
+}
+
+// scan scans the current row into a new record of type t.
+func (p *recordPlan) scan(scan scannerFunc, t reflect.Type) (reflect.Value, error) {
+	holders := make([]reflect.Value, len(p.keys))
+	targets := make([]interface{}, len(p.keys))
+	for i, typ := range p.types {
+		if typ != nil {
+			holders[i] = newRecordHolder(typ)
+			targets[i] = holders[i].Interface()
+		}
+	}
+	if err := scan(targets...); err != nil {
+		return reflect.Value{}, err
+	}
+	record := make(map[string]interface{}, len(p.keys))
+	for i, key := range p.keys {
+		if key == nil {
+			continue
+		}
+		value, err := recordValue(holders[i], p.json[i])
+		if err != nil {
+			return reflect.Value{}, &ScanError{
+				Column:      strings.Join(key, "."),
+				Ordinal:     i,
+				DataTypeOID: p.oids[i],
+				GoType:      interfaceType,
+				Err:         err,
+			}
+		}
+		setRecordKey(record, key, value)
+	}
+	return reflect.ValueOf(record).Convert(t), nil
+}
+
+// newRecordHolder returns the pointer a column is scanned into, typ being
+// its recordValueType.
+func newRecordHolder(typ reflect.Type) reflect.Value {
+	if typ == interfaceType {
+		return reflect.New(typ)
+	}
+	// a nil pointer holds NULL
+	return reflect.New(reflect.PtrTo(typ))
+}
+
+// recordValue returns the value scanned into holder, decoding it when the
+// column is json.
+func recordValue(holder reflect.Value, isJSON bool) (interface{}, error) {
+	v := holder.Elem()
+	if v.Kind() != reflect.Ptr {
+		return v.Interface(), nil
+	}
+	if v.IsNil() {
+		return nil, nil
+	}
+	value := v.Elem().Interface()
+	if isJSON {
+		var decoded interface{}
+		if err := json.Unmarshal(value.([]byte), &decoded); err != nil {
+			return nil, err
+		}
+		return decoded, nil
+	}
+	return value, nil
+}
+
+// isJSONColumn reports whether the postgres type oid is json.
+func isJSONColumn(oid uint32) bool {
+	return oid == pgtype.JSONOID || oid == pgtype.JSONBOID
+}
+
+// setRecordKey sets the value at the key path of record, creating the nested
+// records along the path.
+func setRecordKey(record map[string]interface{}, key []string, value interface{}) {
+	for _, k := range key[:len(key)-1] {
+		next, ok := record[k].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			record[k] = next
+		}
+		record = next
+	}
+	record[key[len(key)-1]] = value
+}
+
+// scanRecords scans the rows into i, a pointer to a record or to a slice of
+// records. A record gets the last row, or ErrTooManyRows with
+// ErrTooManyRowsQuery(true), like a struct.
+func (r *rows) scanRecords(i interface{}) (err error) {
+	val, err := validate(i)
+	if err != nil {
+		return err
+	}
+
+	var rowCount int64
+	defer func() {
+		r.Close()
+		if r.cfg.ReturnErrNoRowsForRows && err == nil && rowCount == 0 {
+			err = pgx.ErrNoRows
+		}
+	}()
+	var plan *recordPlan
+	for r.Next() {
+		if plan == nil {
+			if plan, err = r.recordPlan(); err != nil {
+				return err
+			}
+		}
+		switch val.Kind() {
+		case reflect.Slice:
+			record, err := r.scanRecord(plan, val.Type().Elem())
+			if err != nil {
+				return err
+			}
+			val.Set(reflect.Append(val, record))
+		case reflect.Map:
+			if r.cfg.ReturnErrTooManyRows && rowCount > 0 {
+				return ErrTooManyRows
+			}
+			record, err := r.scanRecord(plan, val.Type())
+			if err != nil {
+				return err
+			}
+			val.Set(record)
+		}
+		rowCount++
+	}
+	return r.Err()
+}
+
+// scanRecord scans the current row into a new record of type t following plan.
+func (r *rows) scanRecord(plan *recordPlan, t reflect.Type) (reflect.Value, error) {
+	record, err := plan.scan(r.rows.Scan, t)
+	if err != nil {
+		return reflect.Value{}, r.valueScanError(err, plan.types...)
+	}
+	return record, nil
+}
+
+// scanRecordInto scans the current row into i, a pointer to a record.
+func (r *rows) scanRecordInto(plan *recordPlan, i interface{}) error {
+	val, err := validate(i)
+	if err != nil {
+		return err
+	}
+	if val.Kind() != reflect.Map {
+		return fmt.Errorf("scanning a single row into %v requires a map destination", val.Type())
+	}
+	record, err := r.scanRecord(plan, val.Type())
+	if err != nil {
+		return err
+	}
+	val.Set(record)
+	return nil
+}
@@ -0,0 +1,41 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rows_ScanRecords(t *testing.T) {
+	stmt := `
+	SELECT "u"."id", "u"."name", 1.50::numeric AS "price", '{"a": 1}'::jsonb AS "data",
+	       0 AS "notate:address", "a"."city"
+	FROM "users" "u"
+	LEFT JOIN "address" "a" ON "a"."user_id" = "u"."id"
+	WHERE "u"."id" <= $1
+	ORDER BY "u"."id"
+	`
+	rows, err := newTestDB(t).Query(context.Background(), stmt, 1)
+	require.NoError(t, err)
+
+	var records []map[string]interface{}
+	require.NoError(t, pgxscan.NewScanner(rows).Scan(&records))
+	require.Equal(t, []map[string]interface{}{{
+		"id":           int64(1),
+		"name":         "user01",
+		"price":        "1.50",
+		"data":         map[string]interface{}{"a": float64(1)},
+		"address.city": "city01",
+	}}, records)
+
+	rows, err = testDB.Query(context.Background(), stmt, 1)
+	require.NoError(t, err)
+	record, err := pgxscan.One[map[string]interface{}](rows, pgxscan.NestedMaps(true))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"city": "city01"}, record["address"])
+}
@@ -0,0 +1,98 @@
+package pgxscan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecordRows() *fakeRows {
+	src := newFakeRows([]string{"id", "name", "active", "data", "created_at", "notate:address", "city", "zip"},
+		[]interface{}{int64(1), "user01", true, []byte(`{"a":[1,"b"]}`), time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), 0, "city01", nil},
+		[]interface{}{int64(2), "user02", false, nil, time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC), 0, nil, nil},
+	)
+	src.fields[3].DataTypeOID = pgtype.JSONBOID
+	src.fields[4].DataTypeOID = pgtype.TimestamptzOID
+	return src
+}
+
+func Test_rows_ScanRecords(t *testing.T) {
+	var records []map[string]interface{}
+	require.NoError(t, NewScanner(newRecordRows()).Scan(&records))
+	require.Equal(t, []map[string]interface{}{
+		{
+			"id": int64(1), "name": "user01", "active": true, "data": map[string]interface{}{"a": []interface{}{float64(1), "b"}},
+			"created_at": time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), "address.city": "city01", "address.zip": nil,
+		},
+		{
+			"id": int64(2), "name": "user02", "active": false, "data": nil,
+			"created_at": time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC), "address.city": nil, "address.zip": nil,
+		},
+	}, records)
+
+	records = nil
+	require.NoError(t, NewScanner(newRecordRows(), NestedMaps(true)).Scan(&records))
+	require.Equal(t, map[string]interface{}{"city": "city01", "zip": nil}, records[0]["address"])
+}
+
+func Test_rows_ScanRecord(t *testing.T) {
+	record := map[string]interface{}{"stale": true}
+	require.NoError(t, NewScanner(newGenericUserRows()).Scan(&record))
+	require.Equal(t, map[string]interface{}{"id": int64(2), "name": "user02"}, record)
+
+	err := NewScanner(newGenericUserRows(), ErrTooManyRowsQuery(true)).Scan(&record)
+	require.Equal(t, ErrTooManyRows, err)
+
+	type Record map[string]interface{}
+	var named Record
+	require.NoError(t, ScanFirst(newGenericUserRows(), &named))
+	require.Equal(t, Record{"id": int64(1), "name": "user01"}, named)
+
+	one, err := One[map[string]interface{}](newFakeRows([]string{"id"}, []interface{}{int64(1)}))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"id": int64(1)}, one)
+
+	all, err := All[map[string]interface{}](newGenericUserRows())
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+}
+
+func Test_rows_ScanRecord_KeyValueMap(t *testing.T) {
+	// maps with other value types still get one entry per row
+	var names map[string]string
+	src := newFakeRows([]string{"name", "email"}, []interface{}{"user01", "user01@email.com"})
+	require.NoError(t, NewScanner(src).Scan(&names))
+	require.Equal(t, map[string]string{"user01": "user01@email.com"}, names)
+}
+
+func Test_Iterator_ScanRecord(t *testing.T) {
+	it := NewIterator(newGenericUserRows())
+	defer it.Close()
+	var records []map[string]interface{}
+	for it.Next() {
+		var record map[string]interface{}
+		require.NoError(t, it.ScanRow(&record))
+		records = append(records, record)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []map[string]interface{}{{"id": int64(1), "name": "user01"}, {"id": int64(2), "name": "user02"}}, records)
+}
+
+func Test_rows_ScanRecord_WantErr(t *testing.T) {
+	src := newFakeRows([]string{"data"}, []interface{}{[]byte(`{`)})
+	src.fields[0].DataTypeOID = pgtype.JSONOID
+	var records []map[string]interface{}
+	err := NewScanner(src).Scan(&records)
+	var scanErr *ScanError
+	require.ErrorAs(t, err, &scanErr)
+	require.Equal(t, "data", scanErr.Column)
+	require.Equal(t, uint32(pgtype.JSONOID), scanErr.DataTypeOID)
+
+	src = newFakeRows([]string{"id"}, []interface{}{"x"})
+	src.fields[0].DataTypeOID = pgtype.Int8OID
+	err = NewScanner(src).Scan(&records)
+	require.ErrorAs(t, err, &scanErr)
+	require.Equal(t, "id", scanErr.Column)
+}
@@ -2,6 +2,8 @@ package pgxscan
 
 import (
 	"github.com/jackc/pgx/v4"
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+	"reflect"
 )
 
 type row struct {
@@ -10,6 +12,9 @@ type row struct {
 	cfg     *Config
 }
 
+// Scan scans the row into i. A single struct destination is only supported
+// when the row columns were declared with the Columns option or SetCols, since
+// pgx.Row does not expose the columns returned by the query.
 func (r *row) Scan(i ...interface{}) error {
 	if i == nil {
 		return nil
@@ -17,6 +22,10 @@ func (r *row) Scan(i ...interface{}) error {
 		if err := r.row.Scan(ii...); err != nil {
 			return err
 		}
+	} else if r.columns != nil && !isVariadic(i...) {
+		if err := r.scanStruct(i[0]); err != nil {
+			return err
+		}
 	} else {
 		if err := r.row.Scan(i...); err != nil {
 			return err
@@ -26,6 +35,25 @@ func (r *row) Scan(i ...interface{}) error {
 	return nil
 }
 
+func (r *row) scanStruct(i interface{}) error {
+	val, err := validate(i)
+	if err != nil {
+		return err
+	}
+	if val.Kind() != reflect.Struct || sqlmaper.ImplementsScanner(val.Type()) {
+		return r.row.Scan(i)
+	}
+	cols, err := r.cfg.Mapper.notateColumns(r.columns)
+	if err != nil {
+		return err
+	}
+	plan, err := r.cfg.Mapper.scanPlan(val.Type(), cols, r.cfg.MatchAllColumnsToStruct, r.cfg.MatchAllFields)
+	if err != nil {
+		return err
+	}
+	return plan.scan(r.row.Scan, val)
+}
+
 func (r *row) SetCols(cols ...string) Scanner {
 	r.columns = cols
 	return r
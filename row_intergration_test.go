@@ -1,3 +1,4 @@
+//go:build integration
 // +build integration
 
 package pgxscan_test
@@ -171,3 +172,58 @@ func Test_scanner_Scan(t *testing.T) {
 		JSONB:       testdata.TestRow1.JSONB,
 	}, ts)
 }
+
+func Test_row_ScanStructWithColumns(t *testing.T) {
+	stmt := `SELECT "id", "name", "email" FROM "users" WHERE "id" = $1`
+	row := newTestDB(t).QueryRow(context.Background(), stmt, 1)
+
+	type User struct {
+		ID    uint32
+		Name  string
+		Email string
+	}
+	var user User
+	err := pgxscan.NewScanner(row, pgxscan.Columns("id", "name", "email")).Scan(&user)
+	require.NoError(t, err)
+	require.Equal(t, User{ID: 1, Name: "user01", Email: "user01@email.com"}, user)
+}
+
+func Test_row_ScanJoinTableWithSetCols(t *testing.T) {
+	stmt := `
+	SELECT users.*,
+	       0 as "notate:address",
+	       address.id, address.line_1, address.city
+	FROM users, address
+	WHERE users.id = $1
+	  AND address.user_id = users.id
+	`
+	row := newTestDB(t).QueryRow(context.Background(), stmt, 1)
+
+	type (
+		Address struct {
+			ID    uint32
+			Line1 string `db:"line_1"`
+			City  string
+		}
+		User struct {
+			ID      uint32
+			Name    string
+			Email   string
+			Address Address `scan:"notate"`
+		}
+	)
+	var user User
+	scanner := pgxscan.NewScanner(row).(pgxscan.ColumnScanner)
+	err := scanner.SetCols("id", "name", "email", "notate:address", "id", "line_1", "city").Scan(&user)
+	require.NoError(t, err)
+	require.Equal(t, User{
+		ID:    1,
+		Name:  "user01",
+		Email: "user01@email.com",
+		Address: Address{
+			ID:    1,
+			Line1: "line01_user01",
+			City:  "city01",
+		},
+	}, user)
+}
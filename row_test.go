@@ -0,0 +1,91 @@
+package pgxscan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRow is an in memory pgx.Row behaving like the one returned by QueryRow.
+type fakeRow struct {
+	rows *fakeRows
+}
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
+
+func Test_row_ScanStructWithColumns(t *testing.T) {
+	src := fakeRow{newFakeRows([]string{"id", "name"}, []interface{}{int64(1), "user01"})}
+
+	var user genericUser
+	err := NewScanner(src, Columns("id", "name")).Scan(&user)
+	require.NoError(t, err)
+	require.Equal(t, genericUser{ID: 1, Name: "user01"}, user)
+}
+
+func Test_row_ScanStructWithSetCols(t *testing.T) {
+	type (
+		Address struct {
+			ID   int64
+			City string
+		}
+		User struct {
+			ID      int64
+			Name    string
+			Address *Address `scan:"notate"`
+		}
+	)
+	src := fakeRow{newFakeRows([]string{"id", "name", "notate:address", "id", "city"},
+		[]interface{}{int64(1), "user01", 0, int64(2), "city01"},
+	)}
+
+	user := new(User)
+	err := NewScanner(src).(ColumnScanner).SetCols("id", "name", "notate:address", "id", "city").Scan(user)
+	require.NoError(t, err)
+	require.Equal(t, &User{ID: 1, Name: "user01", Address: &Address{ID: 2, City: "city01"}}, user)
+}
+
+func Test_row_ScanWithoutColumns(t *testing.T) {
+	src := fakeRow{newFakeRows([]string{"id", "name"}, []interface{}{int64(1), "user01"})}
+
+	var (
+		id   int64
+		name string
+	)
+	err := NewScanner(src).Scan(&id, &name)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), id)
+	require.Equal(t, "user01", name)
+}
+
+func Test_Get_WantErr_TooManyRows(t *testing.T) {
+	q := &fakeQuerier{rows: newGenericUserRows()}
+
+	var user genericUser
+	err := Get(context.Background(), q, &user, `SELECT id, name FROM users`)
+	require.Equal(t, ErrTooManyRows, err)
+	require.True(t, q.rows.closed)
+
+	var id int64
+	q = &fakeQuerier{rows: newFakeRows([]string{"id"}, []interface{}{int64(1)}, []interface{}{int64(2)})}
+	err = Get(context.Background(), q, &id, `SELECT id FROM users`)
+	require.Equal(t, ErrTooManyRows, err)
+}
+
+func Test_Get_WantErr_NoRows(t *testing.T) {
+	var id int64
+	q := &fakeQuerier{rows: newFakeRows([]string{"id"})}
+	err := Get(context.Background(), q, &id, `SELECT id FROM users`)
+	require.Equal(t, pgx.ErrNoRows, err)
+
+	q = &fakeQuerier{rows: newFakeRows([]string{"id"})}
+	err = Get(context.Background(), q, &id, `SELECT id FROM users`, ErrNoRowsQuery(false))
+	require.NoError(t, err)
+}
@@ -10,9 +10,8 @@ import (
 )
 
 type rows struct {
-	rows    pgx.Rows
-	columns []string
-	cfg     *Config
+	rows pgx.Rows
+	cfg  *Config
 }
 
 // Next prepares the next row for Scanning. See sql.Rows#Next for more
@@ -27,6 +26,8 @@ func (r *rows) Err() error {
 	return r.rows.Err()
 }
 
+var ErrTooManyRows = errors.New("too many rows in result set")
+
 var ErrColumnNotateSyntax = errors.New("column notate syntax is invalid: expecting \"notate:name\"")
 var QueryColumnNotatePrefix = "notate:"
 
@@ -71,17 +72,35 @@ var QueryColumnNotatePrefix = "notate:"
 // This helps map values to struct with simple queries without having to list
 // all columns in the SQL.
 func GetColumnNames(rows *pgx.Rows) ([]string, error) {
-	cols := make([]string, 0, len((*rows).FieldDescriptions()))
+	return DefaultMapper.columnNames(*rows)
+}
 
-	notatePrefix := ""
-	for _, field := range (*rows).FieldDescriptions() {
-		colName := string(field.Name)
+// columnNames returns the column names of rows, see GetColumnNames.
+func (m *Mapper) columnNames(rows pgx.Rows) ([]string, error) {
+	fields := rows.FieldDescriptions()
+	if fields == nil {
+		// the source does not report its columns
+		return nil, nil
+	}
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, string(field.Name))
+	}
+	return m.notateColumns(names)
+}
+
+// notateColumns applies the column notations described in GetColumnNames to names.
+func (m *Mapper) notateColumns(names []string) ([]string, error) {
+	cols := make([]string, 0, len(names))
+	prefix := m.notatePrefix()
 
+	notatePrefix := ""
+	for _, colName := range names {
 		// if starts by 'notate:' use what comes after that as the prefix for
 		// all column definitions moving forward
-		if strings.HasPrefix(colName, QueryColumnNotatePrefix) {
+		if strings.HasPrefix(colName, prefix) {
 			// "notate: a.b.c" -> ["notate:", " a.b.c"]
-			splitted := strings.Split(colName, QueryColumnNotatePrefix)
+			splitted := strings.Split(colName, prefix)
 			if len(splitted) != 2 {
 				return nil, ErrColumnNotateSyntax
 			}
@@ -108,6 +127,10 @@ func GetColumnNames(rows *pgx.Rows) ([]string, error) {
 func (r *rows) Scan(i ...interface{}) (err error) {
 	if i == nil {
 		return nil
+	} else if len(i) == 1 && isRecordDest(i[0]) {
+		return r.scanRecords(i[0])
+	} else if len(i) == 1 && isCollection(i[0]) {
+		return r.scanCollection(i[0])
 	} else if isVariadic(i...) {
 		return r.ScanVal(i...)
 	} else if ii, ok := i[0].([]interface{}); ok {
@@ -127,35 +150,50 @@ func (r *rows) Scan(i ...interface{}) (err error) {
 			err = pgx.ErrNoRows
 		}
 	}()
+	var (
+		plan *scanPlan
+		agg  *aggregator
+	)
 	switch val.Kind() {
 	case reflect.Slice:
 		sliceOf := sqlmaper.GetSliceElementType(val)
 		for r.Next() {
+			if plan == nil {
+				if plan, err = r.scanPlan(sliceOf); err != nil {
+					return
+				}
+				if len(plan.many) != 0 {
+					agg = newAggregator(plan)
+				}
+			}
 			sliceVal := reflect.New(sliceOf)
-
-			cols, colErr := GetColumnNames(&r.rows)
-			if colErr != nil {
-				err = colErr
+			if err = r.scanStruct(plan, sliceVal.Elem()); err != nil {
 				return
 			}
-			if ssErr := ScanStruct(r.rows.Scan, sliceVal.Interface(), cols, r.cfg.MatchAllColumnsToStruct); ssErr != nil {
-				err = ssErr
-				return
+			if agg != nil {
+				agg.add(val, sliceVal)
+			} else {
+				sqlmaper.AppendSliceElement(val, sliceVal)
 			}
-			sqlmaper.AppendSliceElement(val, sliceVal)
 			rowCount++
 		}
 	case reflect.Struct:
 		for r.Next() {
+			if r.cfg.ReturnErrTooManyRows && rowCount > 0 {
+				err = ErrTooManyRows
+				return
+			}
 			if val.CanAddr() {
-				cols, colErr := GetColumnNames(&r.rows)
-				if colErr != nil {
-					err = colErr
-					return
+				if plan == nil {
+					if plan, err = r.scanPlan(val.Type()); err != nil {
+						return
+					}
+					if len(plan.many) != 0 {
+						rowCount++
+						return r.scanAggregate(plan, val, r.cfg.ReturnErrTooManyRows, true)
+					}
 				}
-
-				if ssErr := ScanStruct(r.rows.Scan, val.Addr().Interface(), cols, r.cfg.MatchAllColumnsToStruct); ssErr != nil {
-					err = ssErr
+				if err = r.scanStruct(plan, val); err != nil {
 					return
 				}
 			}
@@ -165,6 +203,155 @@ func (r *rows) Scan(i ...interface{}) (err error) {
 	return r.Err()
 }
 
+// scanAggregate scans the rows of a one to many join into val, starting with
+// the current row. The rows with another pk than the first one return
+// ErrTooManyRows when strict is set, else they replace it when last is set,
+// else the scan stops.
+func (r *rows) scanAggregate(plan *scanPlan, val reflect.Value, strict, last bool) error {
+	agg := newAggregator(plan)
+	list := reflect.New(reflect.SliceOf(val.Type())).Elem()
+	for {
+		row := reflect.New(val.Type())
+		if err := r.scanStruct(plan, row.Elem()); err != nil {
+			return err
+		}
+		agg.add(list, row)
+		if list.Len() > 1 {
+			if strict {
+				return ErrTooManyRows
+			}
+			if !last {
+				break
+			}
+		}
+		if !r.Next() {
+			break
+		}
+	}
+	at := 0
+	if last {
+		at = list.Len() - 1
+	}
+	val.Set(list.Index(at))
+	return r.Err()
+}
+
+// scanPlan returns the plan scanning the current result set into t.
+func (r *rows) scanPlan(t reflect.Type) (*scanPlan, error) {
+	cols, err := r.structColumns(t)
+	if err != nil {
+		return nil, err
+	}
+	return r.cfg.Mapper.scanPlan(t, cols, r.cfg.MatchAllColumnsToStruct, r.cfg.MatchAllFields)
+}
+
+// structColumns returns the column names of the result set mapped to the
+// struct type t, routed by table when ResolveTables is set.
+func (r *rows) structColumns(t reflect.Type) ([]string, error) {
+	cols, err := r.cfg.Mapper.columnNames(r.rows)
+	if err != nil || r.cfg.Tables == nil {
+		return cols, err
+	}
+	return r.cfg.Mapper.routeColumns(r.cfg.TablesContext, r.cfg.Tables, t, r.rows.FieldDescriptions(), cols)
+}
+
+// scanStruct scans the current row into val following plan.
+func (r *rows) scanStruct(plan *scanPlan, val reflect.Value) error {
+	err := plan.scanFields(r.rows.Scan, val, r.rows.FieldDescriptions())
+	var scanErr *ScanError
+	if errors.As(err, &scanErr) {
+		if fields := r.rows.FieldDescriptions(); scanErr.Ordinal < len(fields) {
+			scanErr.DataTypeOID = fields[scanErr.Ordinal].DataTypeOID
+		}
+	}
+	return err
+}
+
+// scanOne scans the only row of the result set into i and closes the rows.
+// It returns pgx.ErrNoRows when there is no row, unless turned off with
+// ErrNoRowsQuery(false), and ErrTooManyRows when there is more than one.
+func (r *rows) scanOne(i ...interface{}) error {
+	return r.scanSingle(true, i...)
+}
+
+// scanFirst scans the first row of the result set into i and closes the rows
+// without reading the others. It returns pgx.ErrNoRows when there is no row,
+// unless turned off with ErrNoRowsQuery(false).
+func (r *rows) scanFirst(i ...interface{}) error {
+	return r.scanSingle(false, i...)
+}
+
+func (r *rows) scanSingle(strict bool, i ...interface{}) error {
+	defer r.Close()
+	if i == nil {
+		return nil
+	}
+	if !r.Next() {
+		if err := r.Err(); err != nil {
+			return err
+		}
+		if r.cfg.ReturnErrNoRowsForRows {
+			return pgx.ErrNoRows
+		}
+		return nil
+	}
+	plan, val, err := r.structPlan(i...)
+	if err != nil {
+		return err
+	} else if plan != nil && len(plan.many) != 0 {
+		return r.scanAggregate(plan, val, strict, false)
+	}
+	if err := r.scanRow(plan, val, i...); err != nil {
+		return err
+	}
+	if strict && r.Next() {
+		return ErrTooManyRows
+	}
+	return r.Err()
+}
+
+// scanRow scans the current row into i, which is either a list of values or
+// a single struct. plan and val are the ones structPlan returns for i.
+func (r *rows) scanRow(plan *scanPlan, val reflect.Value, i ...interface{}) error {
+	if len(i) == 1 && isRecordDest(i[0]) {
+		plan, err := r.recordPlan()
+		if err != nil {
+			return err
+		}
+		return r.scanRecordInto(plan, i[0])
+	}
+	if plan == nil {
+		if ii, ok := i[0].([]interface{}); ok && !isVariadic(i...) {
+			return r.rows.Scan(ii...)
+		}
+		return r.rows.Scan(i...)
+	}
+	return r.scanStruct(plan, val)
+}
+
+// structPlan returns the plan scanning the current result set into i when it
+// is a single struct, a nil plan otherwise.
+func (r *rows) structPlan(i ...interface{}) (*scanPlan, reflect.Value, error) {
+	if isVariadic(i...) {
+		return nil, reflect.Value{}, nil
+	} else if _, ok := i[0].([]interface{}); ok {
+		return nil, reflect.Value{}, nil
+	}
+
+	val, err := validate(i[0])
+	if err != nil {
+		return nil, reflect.Value{}, err
+	}
+	if val.Kind() != reflect.Struct || sqlmaper.ImplementsScanner(val.Type()) {
+		return nil, reflect.Value{}, nil
+	}
+	plan, err := r.scanPlan(val.Type())
+	if err != nil {
+		return nil, reflect.Value{}, err
+	}
+	return plan, val, nil
+}
+
 // ScanVal will scan the current row and column into i.
 func (r *rows) ScanVal(v ...interface{}) error {
 	defer r.Close()
@@ -182,7 +369,12 @@ func (r *rows) Close() {
 	r.rows.Close()
 }
 
+// SetCols declares the columns of a RowsSource that does not report them,
+// like the Columns option. The columns of a source describing them, like
+// pgx.Rows, are not changed.
 func (r *rows) SetCols(cols ...string) Scanner {
-	r.columns = cols
+	if src, ok := r.rows.(*sourceRows); ok {
+		src.columns, src.fields = cols, nil
+	}
 	return r
 }
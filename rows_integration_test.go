@@ -1,15 +1,19 @@
+//go:build integration
 // +build integration
 
 package pgxscan_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgtype"
 	"github.com/randallmlough/pgxscan/testdata"
 
 	"github.com/randallmlough/pgxscan"
@@ -500,6 +504,40 @@ func Test_rows_JoinTableWithNotationColumn(t *testing.T) {
 	}, user)
 }
 
+func Test_rows_LeftJoinNullPointerStruct(t *testing.T) {
+	stmt := `
+	SELECT users.id, users.name,
+	       address.id AS "address.id",
+	       address.line_1 AS "address.line_1",
+	       address.city AS "address.city"
+	FROM users
+	LEFT JOIN address ON address.user_id = users.id AND address.user_id = $2
+	WHERE users.id <= $1
+	ORDER BY users.id
+	`
+	rows, err := newTestDB(t).Query(context.Background(), stmt, 2, 1)
+	require.NoError(t, err)
+
+	type (
+		Address struct {
+			ID    uint32
+			Line1 string `db:"line_1"`
+			City  string
+		}
+		User struct {
+			ID      uint32
+			Name    string
+			Address *Address `db:"address,notate,present=id"`
+		}
+	)
+	var users []User
+	require.NoError(t, pgxscan.NewScanner(rows).Scan(&users))
+	require.Equal(t, []User{
+		{ID: 1, Name: "user01", Address: &Address{ID: 1, Line1: "line01_user01", City: "city01"}},
+		{ID: 2, Name: "user02"},
+	}, users)
+}
+
 func Test_rows_JoinConflictTable(t *testing.T) {
 	stmt := `
       SELECT  123 as A,
@@ -584,6 +622,14 @@ func Test_rows_IdentifyNullColumn(t *testing.T) {
 		err.Error(),
 		"can't scan into dest[1] (field 'name'): cannot assign NULL to *string",
 	)
+
+	var scanErr *pgxscan.ScanError
+	require.True(t, errors.As(err, &scanErr))
+	require.Equal(t, "name", scanErr.Column)
+	require.Equal(t, 1, scanErr.Ordinal)
+	require.Equal(t, uint32(pgtype.VarcharOID), scanErr.DataTypeOID)
+	require.Equal(t, reflect.TypeOf(""), scanErr.GoType)
+	require.Equal(t, "Name", scanErr.Field)
 }
 
 func Test_rows_IdentifyWrongTypeForColumn(t *testing.T) {
@@ -609,6 +655,11 @@ func Test_rows_IdentifyWrongTypeForColumn(t *testing.T) {
 		err.Error(),
 		"can't scan into dest[2] (field 'email'): unable to assign to *int",
 	)
+
+	var scanErr *pgxscan.ScanError
+	require.True(t, errors.As(err, &scanErr))
+	require.Equal(t, "Email", scanErr.Field)
+	require.Equal(t, reflect.TypeOf(0), scanErr.GoType)
 }
 
 func Benchmark_rows_JoinPgxBaseline(b *testing.B) {
@@ -758,3 +809,77 @@ func Benchmark_rows_JoinTableWithSqlNotation(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+func Test_rows_ScanOne(t *testing.T) {
+	stmt := `SELECT "id", "name", "email" FROM "users" WHERE "id" < $1 ORDER BY "id"`
+	type User struct {
+		ID    uint32
+		Name  string
+		Email string
+	}
+
+	rows, err := newTestDB(t).Query(context.Background(), stmt, 2)
+	require.NoError(t, err)
+	var user User
+	require.NoError(t, pgxscan.ScanOne(rows, &user))
+	require.Equal(t, User{ID: 1, Name: "user01", Email: "user01@email.com"}, user)
+
+	rows, err = newTestDB(t).Query(context.Background(), stmt, 3)
+	require.NoError(t, err)
+	require.Equal(t, pgxscan.ErrTooManyRows, pgxscan.ScanOne(rows, &user))
+
+	rows, err = newTestDB(t).Query(context.Background(), stmt, 3)
+	require.NoError(t, err)
+	require.Equal(t, pgxscan.ErrTooManyRows, pgxscan.NewScanner(rows, pgxscan.ErrTooManyRowsQuery(true)).Scan(&user))
+}
+
+func Test_rows_ScanFirst(t *testing.T) {
+	stmt := `SELECT "id", "name", "email" FROM "users" ORDER BY "id"`
+	rows, err := newTestDB(t).Query(context.Background(), stmt)
+	require.NoError(t, err)
+
+	type User struct {
+		ID    uint32
+		Name  string
+		Email string
+	}
+	var user User
+	require.NoError(t, pgxscan.ScanFirst(rows, &user))
+	require.Equal(t, User{ID: 1, Name: "user01", Email: "user01@email.com"}, user)
+}
+
+func Test_rows_ScanInline(t *testing.T) {
+	rows, err := newTestDB(t).Query(context.Background(), `SELECT "id", "name", "email", '{"a": 1}'::jsonb AS "data" FROM "users" WHERE "id" = $1`, 1)
+	require.NoError(t, err)
+
+	type User struct {
+		ID    uint32
+		Name  string
+		Extra map[string]interface{} `db:",inline"`
+	}
+	var users []User
+	require.NoError(t, pgxscan.NewScanner(rows).Scan(&users))
+	require.Equal(t, []User{{ID: 1, Name: "user01", Extra: map[string]interface{}{
+		"email": "user01@email.com",
+		"data":  map[string]interface{}{"a": float64(1)},
+	}}}, users)
+}
+
+func Test_rows_MatchAllFields(t *testing.T) {
+	type User struct {
+		ID       uint32
+		Name     string
+		Email    string
+		Nickname string `db:"nickname,optional"`
+	}
+	rows, err := newTestDB(t).Query(context.Background(), `SELECT "id", "name" FROM "users" WHERE "id" = $1`, 1)
+	require.NoError(t, err)
+	var u User
+	err = pgxscan.NewScanner(rows, pgxscan.MatchAllFields(true)).Scan(&u)
+	require.EqualError(t, err, `missing columns for fields of pgxscan_test.User: Email (column "email")`)
+
+	rows, err = testDB.Query(context.Background(), `SELECT "id", "name", "email" FROM "users" WHERE "id" = $1`, 1)
+	require.NoError(t, err)
+	require.NoError(t, pgxscan.NewScanner(rows, pgxscan.MatchAllFields(true)).Scan(&u))
+	require.Equal(t, User{ID: 1, Name: "user01", Email: "user01@email.com"}, u)
+}
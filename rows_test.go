@@ -0,0 +1,261 @@
+package pgxscan
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRows is an in memory pgx.Rows used to unit test the scanning logic
+// without a running database.
+type fakeRows struct {
+	fields []pgproto3.FieldDescription
+	values [][]interface{}
+	idx    int
+	closed bool
+	err    error
+}
+
+var _ pgx.Rows = (*fakeRows)(nil)
+
+func newFakeRows(cols []string, values ...[]interface{}) *fakeRows {
+	fields := make([]pgproto3.FieldDescription, len(cols))
+	for i, col := range cols {
+		fields[i] = pgproto3.FieldDescription{Name: []byte(col), DataTypeOID: fakeTypeOID(values, i)}
+	}
+	return &fakeRows{fields: fields, values: values}
+}
+
+// fakeTypeOID guesses the postgres type of column i from its first non NULL value.
+func fakeTypeOID(values [][]interface{}, i int) uint32 {
+	for _, row := range values {
+		switch row[i].(type) {
+		case nil:
+			continue
+		case int64:
+			return pgtype.Int8OID
+		case string:
+			return pgtype.TextOID
+		case bool:
+			return pgtype.BoolOID
+		}
+		break
+	}
+	return 0
+}
+
+func (r *fakeRows) Close() {
+	r.closed = true
+}
+
+func (r *fakeRows) Err() error {
+	return r.err
+}
+
+func (r *fakeRows) CommandTag() pgconn.CommandTag {
+	return nil
+}
+
+func (r *fakeRows) FieldDescriptions() []pgproto3.FieldDescription {
+	return r.fields
+}
+
+func (r *fakeRows) Next() bool {
+	if r.closed || r.err != nil || r.idx >= len(r.values) {
+		r.closed = true
+		return false
+	}
+	r.idx++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	if len(dest) != len(r.fields) {
+		return fmt.Errorf("number of field descriptions must equal number of destinations, got %d and %d", len(r.fields), len(dest))
+	}
+	row := r.values[r.idx-1]
+	for i, d := range dest {
+		if d == nil {
+			continue
+		}
+		if err := assignFake(d, row[i]); err != nil {
+			r.err = pgx.ScanArgError{ColumnIndex: i, Err: err}
+			r.closed = true
+			return r.err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRows) Values() ([]interface{}, error) {
+	return r.values[r.idx-1], nil
+}
+
+func (r *fakeRows) RawValues() [][]byte {
+	return nil
+}
+
+// assignFake mimics the subset of pgx's assignment rules the tests rely on.
+func assignFake(dst, src interface{}) error {
+	if s, ok := dst.(sql.Scanner); ok {
+		return s.Scan(src)
+	}
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("unable to assign to %T", dst)
+	}
+	dv = dv.Elem()
+	if src == nil {
+		switch dv.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			dv.Set(reflect.Zero(dv.Type()))
+			return nil
+		}
+		return fmt.Errorf("cannot assign NULL to %T", dst)
+	}
+	for dv.Kind() == reflect.Ptr {
+		if dv.IsNil() {
+			dv.Set(reflect.New(dv.Type().Elem()))
+		}
+		dv = dv.Elem()
+	}
+	sv := reflect.ValueOf(src)
+	if !sv.Type().ConvertibleTo(dv.Type()) {
+		return fmt.Errorf("unable to assign to %T", dst)
+	}
+	dv.Set(sv.Convert(dv.Type()))
+	return nil
+}
+
+func Test_rows_ScanSliceOfStructs(t *testing.T) {
+	type User struct {
+		ID   int64
+		Name string
+	}
+	src := newFakeRows([]string{"id", "name"},
+		[]interface{}{int64(1), "user01"},
+		[]interface{}{int64(2), "user02"},
+	)
+
+	var dst []User
+	err := NewScanner(src).Scan(&dst)
+	require.NoError(t, err)
+	require.Equal(t, []User{{ID: 1, Name: "user01"}, {ID: 2, Name: "user02"}}, dst)
+	require.True(t, src.closed)
+}
+
+func Test_rows_ScanNoRows(t *testing.T) {
+	type User struct {
+		ID int64
+	}
+	var dst User
+	err := NewScanner(newFakeRows([]string{"id"})).Scan(&dst)
+	require.Equal(t, pgx.ErrNoRows, err)
+
+	err = NewScanner(newFakeRows([]string{"id"}), ErrNoRowsQuery(false)).Scan(&dst)
+	require.NoError(t, err)
+}
+
+func Test_rows_ErrTooManyRowsQuery(t *testing.T) {
+	var user genericUser
+	err := NewScanner(newGenericUserRows()).Scan(&user)
+	require.NoError(t, err)
+	require.Equal(t, genericUser{ID: 2, Name: "user02"}, user)
+
+	src := newGenericUserRows()
+	err = NewScanner(src, ErrTooManyRowsQuery(true)).Scan(&user)
+	require.Equal(t, ErrTooManyRows, err)
+	require.True(t, src.closed)
+}
+
+func Test_ScanOne(t *testing.T) {
+	var user genericUser
+	err := ScanOne(newFakeRows([]string{"id", "name"}, []interface{}{int64(1), "user01"}), &user)
+	require.NoError(t, err)
+	require.Equal(t, genericUser{ID: 1, Name: "user01"}, user)
+
+	src := newGenericUserRows()
+	err = ScanOne(src, &user)
+	require.Equal(t, ErrTooManyRows, err)
+	require.True(t, src.closed)
+
+	err = ScanOne(newFakeRows([]string{"id", "name"}), &user)
+	require.Equal(t, pgx.ErrNoRows, err)
+
+	var (
+		id   int64
+		name string
+	)
+	err = ScanOne(newFakeRows([]string{"id", "name"}, []interface{}{int64(1), "user01"}), []interface{}{&id, &name})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), id)
+	require.Equal(t, "user01", name)
+}
+
+func Test_ScanFirst(t *testing.T) {
+	src := newGenericUserRows()
+	var user genericUser
+	err := ScanFirst(src, &user)
+	require.NoError(t, err)
+	require.Equal(t, genericUser{ID: 1, Name: "user01"}, user)
+	require.True(t, src.closed)
+	require.Equal(t, 1, src.idx, "only the first row should be read")
+
+	err = ScanFirst(newFakeRows([]string{"id", "name"}), &user, ErrNoRowsQuery(false))
+	require.NoError(t, err)
+}
+
+func Test_rows_MatchAllFields(t *testing.T) {
+	type (
+		Address struct {
+			ID   int64
+			City string
+		}
+		User struct {
+			ID       int64 `db:"id,pk"`
+			Name     string
+			Email    string
+			Nickname string      `db:"nickname,optional"`
+			Address  *Address    `db:"address,notate"`
+			Billing  *Address    `db:"billing,notate,optional"`
+			Orders   []manyOrder `db:"orders,many"`
+		}
+	)
+	newRows := func() *fakeRows {
+		return newFakeRows([]string{"id", "name", "address.id", "orders.id"},
+			[]interface{}{int64(1), "user01", int64(2), int64(10)},
+		)
+	}
+
+	var users []User
+	require.NoError(t, NewScanner(newRows()).Scan(&users))
+
+	err := NewScanner(newRows(), MatchAllFields(true)).Scan(&users)
+	var missingErr *MissingFieldsError
+	require.ErrorAs(t, err, &missingErr)
+	require.Equal(t, []string{"Email", "Address.City", "Orders.Total", "Orders.Items"}, missingErr.Fields)
+	require.Equal(t, []string{"email", "address.city", "orders.total", "orders.items"}, missingErr.Columns)
+	require.EqualError(t, err, `missing columns for fields of pgxscan.User: Email (column "email"), Address.City (column "address.city"), Orders.Total (column "orders.total"), Orders.Items (column "orders.items")`)
+}
+
+func Test_rows_RequiredFields(t *testing.T) {
+	type User struct {
+		ID    int64
+		Name  string
+		Email string `db:"email,required"`
+	}
+	var user User
+	err := NewScanner(newGenericUserRows()).Scan(&user)
+	require.EqualError(t, err, `missing columns for fields of pgxscan.User: Email (column "email")`)
+
+	src := newFakeRows([]string{"id", "name", "email"}, []interface{}{int64(1), "user01", "user01@email.com"})
+	require.NoError(t, ScanOne(src, &user))
+	require.Equal(t, User{ID: 1, Name: "user01", Email: "user01@email.com"}, user)
+}
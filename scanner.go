@@ -1,14 +1,13 @@
 package pgxscan
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v4"
 	"github.com/randallmlough/pgxscan/internal/sqlmapper"
 	"reflect"
-	"regexp"
-	"strconv"
-	"strings"
 	"time"
 )
 
@@ -18,6 +17,15 @@ type (
 		Scan(v ...interface{}) error
 	}
 
+	// ColumnScanner is implemented by every Scanner returned by NewScanner,
+	// including the one of an unsupported source, whose Scan reports the
+	// error. SetCols declares the columns returned by the query, see
+	// Columns, the sources describing their columns like pgx.Rows ignore it.
+	ColumnScanner interface {
+		Scanner
+		SetCols(cols ...string) Scanner
+	}
+
 	scannerFunc func(i ...interface{}) error
 )
 
@@ -28,26 +36,63 @@ func unableToFindFieldError(col string) error {
 // NewScanner takes in a scanner returns a scanner
 // Since the pgx row and rows interface both have a `Scan(v ...interface{}) error` method,
 // either one can be passed as the argument and scanner will take care of the rest.
+// The *sql.Rows and *sql.Row of database/sql are supported as well, and so
+// are the wrapped rows implementing RowsSource. The Scanner returned for an
+// unsupported source, like a nil one, returns an *UnsupportedSourceError.
 func NewScanner(src Scanner, opts ...Option) Scanner {
-	cfg := &Config{
-		ReturnErrNoRowsForRows:  true,
-		MatchAllColumnsToStruct: true,
-	}
-	for _, opt := range opts {
-		opt.apply(cfg)
-	}
+	cfg := newConfig(opts...)
 	switch s := src.(type) {
+	case *sql.Rows:
+		return &rows{rows: SQLRows(s), cfg: cfg}
+	case *sql.Row:
+		return &row{row: sqlRow{row: s}, columns: cfg.Columns, cfg: cfg}
 	case pgx.Rows:
 		return &rows{rows: s, cfg: cfg}
+	case RowsSource:
+		return &rows{rows: newSourceRows(s, cfg.Columns), cfg: cfg}
 	case pgx.Row:
-		return &row{row: s, cfg: cfg}
+		return &row{row: s, columns: cfg.Columns, cfg: cfg}
 	}
-	return nil
+	return errScanner{err: &UnsupportedSourceError{Type: reflect.TypeOf(src)}}
+}
+
+// ScanOne scans the single row returned by src into dst and closes src. dst
+// is a pointer to a struct or a builtin, or a []interface{} of pointers.
+// pgx.ErrNoRows is returned when there is no row, unless turned off with
+// ErrNoRowsQuery(false), and ErrTooManyRows when there is more than one.
+func ScanOne(src pgx.Rows, dst interface{}, opts ...Option) error {
+	return (&rows{rows: src, cfg: newConfig(opts...)}).scanOne(dst)
+}
+
+// ScanFirst scans the first row returned by src into dst and closes src
+// without reading the remaining rows. pgx.ErrNoRows is returned when there is
+// no row, unless turned off with ErrNoRowsQuery(false).
+func ScanFirst(src pgx.Rows, dst interface{}, opts ...Option) error {
+	return (&rows{rows: src, cfg: newConfig(opts...)}).scanFirst(dst)
 }
 
 type Config struct {
 	ReturnErrNoRowsForRows  bool
+	ReturnErrTooManyRows    bool
 	MatchAllColumnsToStruct bool
+	Columns                 []string
+	Mapper                  *Mapper
+	Tables                  *TableResolver
+	TablesContext           context.Context
+	NestedMaps              bool
+	MatchAllFields          bool
+}
+
+func newConfig(opts ...Option) *Config {
+	cfg := &Config{
+		ReturnErrNoRowsForRows:  true,
+		MatchAllColumnsToStruct: true,
+		Mapper:                  DefaultMapper,
+	}
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+	return cfg
 }
 
 type Option interface {
@@ -68,6 +113,15 @@ func ErrNoRowsQuery(b bool) Option {
 	})
 }
 
+// ErrTooManyRowsQuery sets whether or not an ErrTooManyRows error should be
+// returned when a query returning more than one row is scanned into a struct.
+// By default every row is scanned into the struct, leaving it with the last one.
+func ErrTooManyRowsQuery(b bool) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.ReturnErrTooManyRows = b
+	})
+}
+
 // MatchAllColumns sets whether or not a unableToFindFieldError error
 // should be returned on a query that has more columns than fields in the struct
 func MatchAllColumns(b bool) Option {
@@ -76,6 +130,27 @@ func MatchAllColumns(b bool) Option {
 	})
 }
 
+// MatchAllFields sets whether or not a *MissingFieldsError should be
+// returned when the result set has no column for a mapped field. Fields with
+// the optional tag option, or in a nested struct with it, are exempt. Fields
+// with the required tag option are always checked.
+func MatchAllFields(b bool) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.MatchAllFields = b
+	})
+}
+
+// Columns declares the columns returned by the query, in order. It is needed
+// to scan a pgx.Row, which does not expose its columns, into a struct.
+//
+//	row := conn.QueryRow(ctx, `SELECT "id", "name" FROM "users" WHERE "id" = $1`, 1)
+//	err := pgxscan.NewScanner(row, pgxscan.Columns("id", "name")).Scan(&user)
+func Columns(cols ...string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Columns = cols
+	})
+}
+
 var ErrNoCols = errors.New("columns can not be nil")
 
 // ScanStruct will scan the current row into i.
@@ -86,54 +161,13 @@ func ScanStruct(scan scannerFunc, i interface{}, cols []string, matchAllColumnsT
 	if cols == nil {
 		return ErrNoCols
 	}
-	cm, err := sqlmaper.GetColumnMap(i)
+	val := reflect.Indirect(reflect.ValueOf(i))
+	t, _ := sqlmaper.GetTypeInfo(i, val)
+	plan, err := DefaultMapper.scanPlan(t, cols, matchAllColumnsToStruct, false)
 	if err != nil {
 		return err
 	}
-
-	scans := make([]interface{}, len(cols))
-	for idx, col := range cols {
-		data, ok := cm[col]
-		switch {
-		case strings.HasPrefix(col, QueryColumnNotatePrefix):
-			// notated columns are always skipped
-			scans[idx] = new(int8)
-		case !ok:
-			if matchAllColumnsToStruct {
-				return unableToFindFieldError(col)
-			}
-		default:
-			scans[idx] = reflect.New(data.GoType).Interface()
-		}
-	}
-
-	if err := scan(scans...); err != nil {
-		// identify the offending field in case types do not match, very useful
-		// when using this library
-		if strings.HasPrefix(err.Error(), "can't scan into dest[") {
-			// TODO: use ScanArgError if made public (see https://github.com/jackc/pgx/issues/931)
-			//       to avoid all this parsing
-			splitted := strings.Split(err.Error(), ":")
-			re := regexp.MustCompile(`\[(\d+)\]`)
-			errCol, errConv := strconv.Atoi(
-				strings.Trim(string(re.Find([]byte(splitted[0]))), "[]"),
-			)
-			if errConv != nil {
-				return err
-			}
-			return fmt.Errorf("%s (field '%s'):%s", splitted[0], cols[errCol], splitted[1])
-		}
-		return err
-	}
-
-	record := make(map[string]interface{}, len(cols))
-	for index, col := range cols {
-		record[col] = scans[index]
-	}
-
-	sqlmaper.AssignStructVals(i, record, cm)
-
-	return nil
+	return plan.scan(scan, val)
 }
 
 func validate(i interface{}) (reflect.Value, error) {
@@ -0,0 +1,144 @@
+package pgxscan
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+// RowsSource is the part of pgx.Rows needed to scan a result set. Wrapped or
+// instrumented rows, like tracing decorators, only need these methods to be
+// scanned by NewScanner or adapted by AsRows. Structs are scanned when the
+// source also implements FieldDescriber or ColumnProvider, or when the
+// columns are declared with the Columns option.
+type RowsSource interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+	Close()
+}
+
+// FieldDescriber is implemented by the sources describing their columns like
+// pgx.Rows does.
+type FieldDescriber interface {
+	FieldDescriptions() []pgproto3.FieldDescription
+}
+
+// ColumnProvider is implemented by the sources listing their column names
+// like *sql.Rows does.
+type ColumnProvider interface {
+	Columns() ([]string, error)
+}
+
+// UnsupportedSourceError is returned when scanning a source that is neither a
+// pgx.Rows, a pgx.Row, a RowsSource nor a database/sql one.
+type UnsupportedSourceError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedSourceError) Error() string {
+	return fmt.Sprintf("unsupported scan source %v: expecting pgx.Rows, pgx.Row or a RowsSource", e.Type)
+}
+
+// errScanner is returned by NewScanner for an unsupported source, so the
+// error is reported by Scan instead of a nil Scanner panicking.
+type errScanner struct {
+	err error
+}
+
+func (s errScanner) Scan(...interface{}) error {
+	return s.err
+}
+
+func (s errScanner) SetCols(...string) Scanner {
+	return s
+}
+
+// AsRows adapts src to pgx.Rows, so it can be scanned by All, One, ScanOne or
+// an Iterator. src is a pgx.Rows, a *sql.Rows or a RowsSource, anything else
+// returns an *UnsupportedSourceError. The Columns option declares the columns
+// of a source that does not report them.
+func AsRows(src interface{}, opts ...Option) (pgx.Rows, error) {
+	switch s := src.(type) {
+	case pgx.Rows:
+		return s, nil
+	case *sql.Rows:
+		return SQLRows(s), nil
+	case RowsSource:
+		return newSourceRows(s, newConfig(opts...).Columns), nil
+	}
+	return nil, &UnsupportedSourceError{Type: reflect.TypeOf(src)}
+}
+
+// sourceRows is a pgx.Rows reading a RowsSource, the optional methods are
+// used when the source has them.
+type sourceRows struct {
+	RowsSource
+	columns []string
+	fields  []pgproto3.FieldDescription
+}
+
+var _ pgx.Rows = (*sourceRows)(nil)
+
+func newSourceRows(src RowsSource, columns []string) *sourceRows {
+	return &sourceRows{RowsSource: src, columns: columns}
+}
+
+// FieldDescriptions returns the field descriptions of the source, or fields
+// named after its column names. It returns nil when the columns are unknown.
+func (r *sourceRows) FieldDescriptions() []pgproto3.FieldDescription {
+	if src, ok := r.RowsSource.(FieldDescriber); ok {
+		return src.FieldDescriptions()
+	}
+	if r.fields != nil {
+		return r.fields
+	}
+	cols := r.columns
+	if src, ok := r.RowsSource.(ColumnProvider); ok && cols == nil {
+		var err error
+		if cols, err = src.Columns(); err != nil {
+			return nil
+		}
+	}
+	if cols == nil {
+		return nil
+	}
+	r.fields = make([]pgproto3.FieldDescription, len(cols))
+	for i, col := range cols {
+		r.fields[i].Name = []byte(col)
+	}
+	return r.fields
+}
+
+func (r *sourceRows) CommandTag() pgconn.CommandTag {
+	if src, ok := r.RowsSource.(interface{ CommandTag() pgconn.CommandTag }); ok {
+		return src.CommandTag()
+	}
+	return nil
+}
+
+func (r *sourceRows) Values() ([]interface{}, error) {
+	if src, ok := r.RowsSource.(interface{ Values() ([]interface{}, error) }); ok {
+		return src.Values()
+	}
+	values := make([]interface{}, len(r.FieldDescriptions()))
+	dest := make([]interface{}, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := r.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (r *sourceRows) RawValues() [][]byte {
+	if src, ok := r.RowsSource.(interface{ RawValues() [][]byte }); ok {
+		return src.RawValues()
+	}
+	return nil
+}
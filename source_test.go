@@ -0,0 +1,98 @@
+package pgxscan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgproto3/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// tracedRows decorates rows like a tracing wrapper would, exposing only the
+// methods of RowsSource.
+type tracedRows struct {
+	rows  *fakeRows
+	scans int
+}
+
+func (r *tracedRows) Next() bool { return r.rows.Next() }
+func (r *tracedRows) Err() error { return r.rows.Err() }
+func (r *tracedRows) Close()     { r.rows.Close() }
+func (r *tracedRows) Scan(dest ...interface{}) error {
+	r.scans++
+	return r.rows.Scan(dest...)
+}
+
+// describedRows is a tracedRows reporting its field descriptions.
+type describedRows struct {
+	tracedRows
+}
+
+func (r *describedRows) FieldDescriptions() []pgproto3.FieldDescription {
+	return r.rows.FieldDescriptions()
+}
+
+// listedRows is a tracedRows listing its column names.
+type listedRows struct {
+	tracedRows
+}
+
+func (r *listedRows) Columns() ([]string, error) {
+	var cols []string
+	for _, f := range r.rows.FieldDescriptions() {
+		cols = append(cols, string(f.Name))
+	}
+	return cols, nil
+}
+
+var wantGenericUsers = []genericUser{{ID: 1, Name: "user01"}, {ID: 2, Name: "user02"}}
+
+func Test_NewScanner_RowsSource(t *testing.T) {
+	src := &describedRows{tracedRows{rows: newGenericUserRows()}}
+	var users []genericUser
+	require.NoError(t, NewScanner(src).Scan(&users))
+	require.Equal(t, wantGenericUsers, users)
+	require.Equal(t, 2, src.scans)
+	require.True(t, src.rows.closed)
+
+	users = nil
+	require.NoError(t, NewScanner(&listedRows{tracedRows{rows: newGenericUserRows()}}).Scan(&users))
+	require.Equal(t, wantGenericUsers, users)
+
+	users = nil
+	err := NewScanner(&tracedRows{rows: newGenericUserRows()}, Columns("id", "name")).Scan(&users)
+	require.NoError(t, err)
+	require.Equal(t, wantGenericUsers, users)
+
+	users = nil
+	err = NewScanner(&tracedRows{rows: newGenericUserRows()}).(ColumnScanner).SetCols("id", "name").Scan(&users)
+	require.NoError(t, err)
+	require.Equal(t, wantGenericUsers, users)
+
+	users = nil
+	err = NewScanner(&tracedRows{rows: newGenericUserRows()}).Scan(&users)
+	require.Equal(t, ErrNoCols, err)
+}
+
+func Test_NewScanner_WantErr_UnsupportedSource(t *testing.T) {
+	scanner := NewScanner(nil)
+	require.NotNil(t, scanner)
+
+	var user genericUser
+	err := scanner.(ColumnScanner).SetCols("id", "name").Scan(&user)
+	var sourceErr *UnsupportedSourceError
+	require.True(t, errors.As(err, &sourceErr))
+	require.EqualError(t, err, "unsupported scan source <nil>: expecting pgx.Rows, pgx.Row or a RowsSource")
+}
+
+func Test_AsRows(t *testing.T) {
+	src, err := AsRows(&tracedRows{rows: newGenericUserRows()}, Columns("id", "name"))
+	require.NoError(t, err)
+	users, err := All[genericUser](src)
+	require.NoError(t, err)
+	require.Equal(t, wantGenericUsers, users)
+
+	_, err = AsRows(struct{}{})
+	var sourceErr *UnsupportedSourceError
+	require.True(t, errors.As(err, &sourceErr))
+}
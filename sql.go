@@ -0,0 +1,169 @@
+package pgxscan
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+// SQLRows adapts a *sql.Rows to pgx.Rows, so the rows of a database/sql
+// query can be scanned by All, One, ScanOne or an Iterator with the same
+// struct definitions as the rows of pgx. NewScanner adapts *sql.Rows on
+// its own.
+//
+//	rows, _ := db.QueryContext(ctx, `SELECT * FROM users`)
+//	users, err := pgxscan.All[User](pgxscan.SQLRows(rows))
+func SQLRows(rows *sql.Rows) pgx.Rows {
+	return &sqlRows{rows: rows}
+}
+
+// sqlRows is a pgx.Rows reading a *sql.Rows. The field descriptions hold the
+// column names and, when the driver reports them, the type OIDs resolved
+// from the database type names.
+type sqlRows struct {
+	rows   *sql.Rows
+	fields []pgproto3.FieldDescription
+	err    error
+	closed bool
+}
+
+var _ pgx.Rows = (*sqlRows)(nil)
+
+func (r *sqlRows) Close() {
+	if !r.closed {
+		r.closed = true
+		if err := r.rows.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+	}
+}
+
+func (r *sqlRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.rows.Err()
+}
+
+func (r *sqlRows) CommandTag() pgconn.CommandTag {
+	return nil
+}
+
+func (r *sqlRows) FieldDescriptions() []pgproto3.FieldDescription {
+	if r.fields != nil || r.err != nil {
+		return r.fields
+	}
+	cols, err := r.rows.Columns()
+	if err != nil {
+		r.err = err
+		return nil
+	}
+	fields := make([]pgproto3.FieldDescription, len(cols))
+	for i, col := range cols {
+		fields[i].Name = []byte(col)
+	}
+	if types, err := r.rows.ColumnTypes(); err == nil {
+		for i, typ := range types {
+			if dt, ok := defaultConnInfo.DataTypeForName(strings.ToLower(typ.DatabaseTypeName())); ok {
+				fields[i].DataTypeOID = dt.OID
+			}
+		}
+	}
+	r.fields = fields
+	return r.fields
+}
+
+func (r *sqlRows) Next() bool {
+	if r.closed {
+		return false
+	}
+	if !r.rows.Next() {
+		r.Close()
+		return false
+	}
+	return true
+}
+
+func (r *sqlRows) Scan(dest ...interface{}) error {
+	targets := sqlTargets(dest)
+	if err := r.rows.Scan(targets...); err != nil {
+		err = r.scanArgError(targets, err)
+		r.err = err
+		r.Close()
+		return err
+	}
+	return nil
+}
+
+// scanArgError converts err, returned by the scan of the current row into
+// targets, to a pgx.ScanArgError so it is reported as a ScanError. database/sql
+// only names the failing column in the error text, so the columns are scanned
+// again one at a time, the others being discarded, to find it.
+func (r *sqlRows) scanArgError(targets []interface{}, err error) error {
+	probe := make([]interface{}, len(targets))
+	for i := range probe {
+		probe[i] = new(interface{})
+	}
+	if r.rows.Scan(probe...) != nil {
+		// the row itself can not be read
+		return err
+	}
+	for i, target := range targets {
+		discard := probe[i]
+		probe[i] = target
+		if probeErr := r.rows.Scan(probe...); probeErr != nil {
+			// database/sql wraps the error of the conversion with the column
+			if inner := errors.Unwrap(probeErr); inner != nil {
+				probeErr = inner
+			}
+			return pgx.ScanArgError{ColumnIndex: i, Err: probeErr}
+		}
+		probe[i] = discard
+	}
+	return err
+}
+
+func (r *sqlRows) Values() ([]interface{}, error) {
+	values := make([]interface{}, len(r.FieldDescriptions()))
+	dest := make([]interface{}, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (r *sqlRows) RawValues() [][]byte {
+	return nil
+}
+
+// sqlRow is a pgx.Row reading a *sql.Row.
+type sqlRow struct {
+	row *sql.Row
+}
+
+func (r sqlRow) Scan(dest ...interface{}) error {
+	// the row can not be scanned again to find the failing column, its error
+	// is returned as is
+	return r.row.Scan(sqlTargets(dest)...)
+}
+
+// sqlTargets returns the destinations passed to database/sql for dest.
+// Unlike pgx, database/sql does not skip the nil destinations, they are
+// replaced by a discarded value.
+func sqlTargets(dest []interface{}) []interface{} {
+	targets := make([]interface{}, len(dest))
+	for i, d := range dest {
+		if d == nil {
+			d = new(interface{})
+		}
+		targets[i] = d
+	}
+	return targets
+}
@@ -0,0 +1,77 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4/stdlib"
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewScanner_SQLRows(t *testing.T) {
+	db := stdlib.OpenDB(*testDB.Config().ConnConfig)
+	defer db.Close()
+
+	stmt := `
+	SELECT users.*,
+	       0 as "notate:address", -- delimiter column
+	       address.*
+	FROM users, address
+	WHERE users.id = $1
+	  AND address.user_id = users.id
+	`
+	type (
+		Address struct {
+			ID    uint32
+			Line1 string `db:"line_1"`
+			City  string
+		}
+		User struct {
+			ID      uint32
+			Name    string
+			Email   string
+			Address Address `scan:"notate"`
+		}
+	)
+	want := User{
+		ID:    1,
+		Name:  "user01",
+		Email: "user01@email.com",
+		Address: Address{
+			ID:    1,
+			Line1: "line01_user01",
+			City:  "city01",
+		},
+	}
+
+	rows, err := db.QueryContext(context.Background(), stmt, 1)
+	require.NoError(t, err)
+	var user User
+	require.NoError(t, pgxscan.NewScanner(rows, pgxscan.MatchAllColumns(false)).Scan(&user))
+	require.Equal(t, want, user)
+
+	rows, err = db.QueryContext(context.Background(), stmt, 1)
+	require.NoError(t, err)
+	users, err := pgxscan.All[User](pgxscan.SQLRows(rows), pgxscan.MatchAllColumns(false))
+	require.NoError(t, err)
+	require.Equal(t, []User{want}, users)
+}
+
+func Test_NewScanner_SQLRow(t *testing.T) {
+	db := stdlib.OpenDB(*testDB.Config().ConnConfig)
+	defer db.Close()
+
+	type User struct {
+		ID    uint32
+		Name  string
+		Email string
+	}
+	row := db.QueryRowContext(context.Background(), `SELECT "id", "name", "email" FROM "users" WHERE "id" = $1`, 1)
+	var user User
+	require.NoError(t, pgxscan.NewScanner(row, pgxscan.Columns("id", "name", "email")).Scan(&user))
+	require.Equal(t, User{ID: 1, Name: "user01", Email: "user01@email.com"}, user)
+}
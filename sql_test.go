@@ -0,0 +1,170 @@
+package pgxscan
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a database/sql driver answering every query with the
+// columns, types and values of fakeSQLResult.
+type fakeDriver struct{}
+
+type (
+	fakeSQLConn   struct{}
+	fakeSQLStmt   struct{}
+	fakeSQLResult struct {
+		cols   []string
+		types  []string
+		values [][]driver.Value
+		idx    int
+	}
+)
+
+var fakeSQLRows *fakeSQLResult
+
+func init() {
+	sql.Register("pgxscan-fake", fakeDriver{})
+}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeSQLConn{}, nil }
+
+func (fakeSQLConn) Prepare(string) (driver.Stmt, error) { return fakeSQLStmt{}, nil }
+func (fakeSQLConn) Close() error                        { return nil }
+func (fakeSQLConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (fakeSQLStmt) Close() error  { return nil }
+func (fakeSQLStmt) NumInput() int { return -1 }
+func (fakeSQLStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (fakeSQLStmt) Query([]driver.Value) (driver.Rows, error) {
+	res := *fakeSQLRows
+	return &res, nil
+}
+
+func (r *fakeSQLResult) Columns() []string { return r.cols }
+func (r *fakeSQLResult) Close() error      { return nil }
+func (r *fakeSQLResult) ColumnTypeDatabaseTypeName(i int) string {
+	return r.types[i]
+}
+func (r *fakeSQLResult) Next(dest []driver.Value) error {
+	if r.idx >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.idx])
+	r.idx++
+	return nil
+}
+
+func newFakeDB(t *testing.T, result *fakeSQLResult) *sql.DB {
+	fakeSQLRows = result
+	db, err := sql.Open("pgxscan-fake", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newFakeSQLUsers() *fakeSQLResult {
+	return &fakeSQLResult{
+		cols:  []string{"id", "name", "notate:address", "id", "city"},
+		types: []string{"INT8", "TEXT", "INT4", "INT8", "TEXT"},
+		values: [][]driver.Value{
+			{int64(1), "user01", int64(0), int64(2), "city01"},
+			{int64(2), "user02", int64(0), nil, nil},
+		},
+	}
+}
+
+type sqlAddress struct {
+	ID   int64
+	City string
+}
+
+type sqlUser struct {
+	ID      int64
+	Name    string
+	Address *sqlAddress `scan:"notate"`
+}
+
+func Test_NewScanner_SQLRows(t *testing.T) {
+	rows, err := newFakeDB(t, newFakeSQLUsers()).Query(`SELECT`)
+	require.NoError(t, err)
+
+	var users []sqlUser
+	require.NoError(t, NewScanner(rows).Scan(&users))
+	require.Equal(t, []sqlUser{
+		{ID: 1, Name: "user01", Address: &sqlAddress{ID: 2, City: "city01"}},
+		{ID: 2, Name: "user02"},
+	}, users)
+	_, err = rows.Columns()
+	require.Error(t, err, "rows should be closed")
+}
+
+func Test_SQLRows(t *testing.T) {
+	rows, err := newFakeDB(t, newFakeSQLUsers()).Query(`SELECT`)
+	require.NoError(t, err)
+
+	src := SQLRows(rows)
+	fields := src.FieldDescriptions()
+	require.Equal(t, "city", string(fields[4].Name))
+	require.Equal(t, uint32(pgtype.Int8OID), fields[0].DataTypeOID)
+	require.Equal(t, uint32(pgtype.TextOID), fields[1].DataTypeOID)
+
+	user, err := One[sqlUser](src)
+	require.Equal(t, ErrTooManyRows, err)
+	require.Equal(t, sqlUser{}, user)
+
+	rows, err = newFakeDB(t, newFakeSQLUsers()).Query(`SELECT`)
+	require.NoError(t, err)
+	users, err := All[sqlUser](SQLRows(rows))
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+}
+
+func Test_NewScanner_SQLRow(t *testing.T) {
+	row := newFakeDB(t, newFakeSQLUsers()).QueryRow(`SELECT`)
+
+	var user sqlUser
+	err := NewScanner(row, Columns("id", "name", "notate:address", "id", "city")).Scan(&user)
+	require.NoError(t, err)
+	require.Equal(t, sqlUser{ID: 1, Name: "user01", Address: &sqlAddress{ID: 2, City: "city01"}}, user)
+}
+
+func Test_NewScanner_SQLRows_WantErr_ScanError(t *testing.T) {
+	type User struct {
+		ID   int64
+		Name int64
+	}
+	rows, err := newFakeDB(t, newFakeSQLUsers()).Query(`SELECT`)
+	require.NoError(t, err)
+
+	var users []User
+	err = NewScanner(rows, MatchAllColumns(false)).Scan(&users)
+	var scanErr *ScanError
+	require.True(t, errors.As(err, &scanErr))
+	require.Equal(t, "name", scanErr.Column)
+	require.Equal(t, 1, scanErr.Ordinal)
+	require.Equal(t, "Name", scanErr.Field)
+	require.Equal(t, uint32(pgtype.TextOID), scanErr.DataTypeOID)
+}
+
+func Test_NewScanner_SQLRow_WantErr(t *testing.T) {
+	type User struct {
+		ID   int64
+		Name int64
+	}
+	row := newFakeDB(t, newFakeSQLUsers()).QueryRow(`SELECT`)
+
+	// the column of the error of a *sql.Row is not known
+	var user User
+	err := NewScanner(row, Columns("id", "name", "notate:address", "id", "city"), MatchAllColumns(false)).Scan(&user)
+	require.Error(t, err)
+	var scanErr *ScanError
+	require.False(t, errors.As(err, &scanErr))
+}
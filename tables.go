@@ -0,0 +1,191 @@
+package pgxscan
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgproto3/v2"
+	sqlmaper "github.com/randallmlough/pgxscan/internal/sqlmapper"
+)
+
+// TableResolver resolves the TableOID of the result columns to schema
+// qualified table names, looking them up in pg_class and pg_namespace. Names
+// are cached, so use one TableResolver per connection or pool.
+//
+// With the ResolveTables option, the columns of a table are routed to the
+// nested struct field with the table tag option, no notate column is needed
+// and duplicate column names like "id" are told apart by their table:
+//
+//	type User struct {
+//	    ID      int64
+//	    Name    string
+//	    Address Address `db:"address,table=addresses"`
+//	}
+//
+//	tables := pgxscan.NewTableResolver(pool)
+//	rows, _ := pool.Query(ctx, `SELECT u.*, a.* FROM users u JOIN addresses a ON a.user_id = u.id`)
+//	err := pgxscan.NewScanner(rows, pgxscan.ResolveTables(ctx, tables)).Scan(&users)
+//
+// A table option without a schema, like table=addresses, matches the table
+// of that name found in the search path, and one with a schema, like
+// table=sales.addresses, only matches the table of that schema.
+//
+// The unknown OIDs are looked up on the Querier of the resolver while the rows
+// are still open, so it needs a second connection: a pool, or another
+// connection than the one the rows are read from. Call Preload before the
+// query when there is none, like when scanning the rows of a pgx.Tx.
+type TableResolver struct {
+	q      Querier
+	lock   sync.RWMutex
+	tables map[uint32]table
+}
+
+// NewTableResolver returns a TableResolver looking up the table names on q.
+func NewTableResolver(q Querier) *TableResolver {
+	return &TableResolver{q: q, tables: make(map[uint32]table)}
+}
+
+// ResolveTables routes the columns to the nested struct fields with the
+// table tag option by the table they come from, see TableResolver. The
+// unknown tables are looked up with ctx, usually the context of the query.
+func ResolveTables(ctx context.Context, r *TableResolver) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Tables = r
+		cfg.TablesContext = ctx
+	})
+}
+
+// tablesQuery selects the tables of pg_class with their schema.
+const tablesQuery = `SELECT "c"."oid"::int8, "n"."nspname"::text, "c"."relname"::text, pg_table_is_visible("c"."oid") AS "visible" ` +
+	`FROM "pg_class" "c" JOIN "pg_namespace" "n" ON "n"."oid" = "c"."relnamespace"`
+
+// Preload caches the names of every table, view and materialized view of the
+// database.
+func (r *TableResolver) Preload(ctx context.Context) error {
+	return r.load(ctx, tablesQuery+` WHERE "c"."relkind" IN ('r', 'v', 'm', 'p', 'f')`)
+}
+
+// Resolve returns the schema qualified names, like "public.users", of the
+// tables with the given OIDs, looking up the ones that are not cached.
+// Unknown OIDs are left out.
+func (r *TableResolver) Resolve(ctx context.Context, oids ...uint32) (map[uint32]string, error) {
+	tables, err := r.resolve(ctx, oids)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint32]string, len(tables))
+	for oid, t := range tables {
+		names[oid] = t.qualifiedName()
+	}
+	return names, nil
+}
+
+func (r *TableResolver) resolve(ctx context.Context, oids []uint32) (map[uint32]table, error) {
+	var missing []int64
+	r.lock.RLock()
+	tables := make(map[uint32]table, len(oids))
+	for _, oid := range oids {
+		if t, ok := r.tables[oid]; ok {
+			tables[oid] = t
+		} else if _, ok := tables[oid]; !ok && oid != 0 {
+			// the zero table marks the oid as missing
+			tables[oid] = table{}
+			missing = append(missing, int64(oid))
+		}
+	}
+	r.lock.RUnlock()
+	if len(missing) == 0 {
+		return tables, nil
+	}
+
+	if err := r.load(ctx, tablesQuery+` WHERE "c"."oid"::int8 = ANY($1)`, missing); err != nil {
+		return nil, err
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, oid := range missing {
+		if t, ok := r.tables[uint32(oid)]; ok {
+			tables[uint32(oid)] = t
+		} else {
+			delete(tables, uint32(oid))
+		}
+	}
+	return tables, nil
+}
+
+func (r *TableResolver) load(ctx context.Context, sql string, args ...interface{}) error {
+	rows, err := r.q.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	tables, err := All[table](rows)
+	if err != nil {
+		return err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, t := range tables {
+		r.tables[uint32(t.OID)] = t
+	}
+	return nil
+}
+
+type table struct {
+	OID    int64  `db:"oid"`
+	Schema string `db:"nspname"`
+	Name   string `db:"relname"`
+	// Visible is set when the table is found in the search path, so it can
+	// be referred to without its schema.
+	Visible bool `db:"visible"`
+}
+
+func (t table) qualifiedName() string {
+	return t.Schema + "." + t.Name
+}
+
+// prefix returns the column prefix of t in tables, keyed by the table tag
+// options.
+func (t table) prefix(tables sqlmaper.TableMap) (string, bool) {
+	if t.Name == "" {
+		return "", false
+	}
+	if prefix, ok := tables[t.qualifiedName()]; ok {
+		return prefix, true
+	}
+	if !t.Visible {
+		return "", false
+	}
+	prefix, ok := tables[t.Name]
+	return prefix, ok
+}
+
+// routeColumns prefixes the columns coming from the tables of the nested
+// struct fields of t with the table tag option. Columns that are already
+// notated or aliased with a dot are left as they are.
+func (m *Mapper) routeColumns(ctx context.Context, r *TableResolver, t reflect.Type, fields []pgproto3.FieldDescription, cols []string) ([]string, error) {
+	tables, err := m.mapper.GetTableMap(reflect.New(t).Interface())
+	if err != nil || len(tables) == 0 {
+		return cols, err
+	}
+	oids := make([]uint32, len(fields))
+	for i, field := range fields {
+		oids[i] = field.TableOID
+	}
+	resolved, err := r.resolve(ctx, oids)
+	if err != nil {
+		return nil, err
+	}
+	routed := make([]string, len(cols))
+	for i, col := range cols {
+		routed[i] = col
+		if i >= len(fields) || strings.Contains(col, ".") || strings.HasPrefix(col, m.notatePrefix()) {
+			continue
+		}
+		if prefix, ok := resolved[fields[i].TableOID].prefix(tables); ok {
+			routed[i] = prefix + "." + col
+		}
+	}
+	return routed, nil
+}
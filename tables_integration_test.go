@@ -0,0 +1,64 @@
+//go:build integration
+// +build integration
+
+package pgxscan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/randallmlough/pgxscan"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rows_ResolveTables(t *testing.T) {
+	type (
+		Address struct {
+			ID    uint32
+			Line1 string `db:"line_1"`
+			City  string
+		}
+		User struct {
+			ID      uint32
+			Name    string
+			Email   string
+			Address Address `db:"address,table=address"`
+		}
+	)
+	stmt := `
+	SELECT users.*, address.*
+	FROM users, address
+	WHERE users.id = $1
+	  AND address.user_id = users.id
+	`
+	rows, err := testDB.Query(context.Background(), stmt, 1)
+	require.NoError(t, err)
+
+	var user User
+	tables := pgxscan.NewTableResolver(testDB)
+	err = pgxscan.NewScanner(rows, pgxscan.ResolveTables(context.Background(), tables), pgxscan.MatchAllColumns(false)).Scan(&user)
+	require.NoError(t, err)
+	require.Equal(t, User{
+		ID:    1,
+		Name:  "user01",
+		Email: "user01@email.com",
+		Address: Address{
+			ID:    1,
+			Line1: "line01_user01",
+			City:  "city01",
+		},
+	}, user)
+}
+
+func Test_TableResolver_Preload(t *testing.T) {
+	conn := newTestDB(t)
+	tables := pgxscan.NewTableResolver(conn)
+	require.NoError(t, tables.Preload(context.Background()))
+
+	var oid uint32
+	err := conn.QueryRow(context.Background(), `SELECT 'users'::regclass::oid`).Scan(&oid)
+	require.NoError(t, err)
+	names, err := tables.Resolve(context.Background(), oid)
+	require.NoError(t, err)
+	require.Equal(t, map[uint32]string{oid: "public.users"}, names)
+}
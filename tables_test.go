@@ -0,0 +1,90 @@
+package pgxscan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rows_ResolveTables(t *testing.T) {
+	type (
+		Address struct {
+			ID   int64
+			City string
+		}
+		User struct {
+			ID      int64
+			Name    string
+			Address *Address `db:"address,table=addresses"`
+		}
+	)
+	src := newFakeRows([]string{"id", "name", "id", "city"},
+		[]interface{}{int64(1), "user01", int64(2), "city01"},
+	)
+	for i, oid := range []uint32{100, 100, 200, 200} {
+		src.fields[i].TableOID = oid
+	}
+	q := &fakeQuerier{rows: newTableRows()}
+	tables := NewTableResolver(q)
+
+	var user User
+	require.NoError(t, NewScanner(src, ResolveTables(context.Background(), tables)).Scan(&user))
+	require.Equal(t, User{ID: 1, Name: "user01", Address: &Address{ID: 2, City: "city01"}}, user)
+	require.Equal(t, []interface{}{[]int64{100, 200}}, q.args)
+
+	// names are cached
+	q.args = nil
+	names, err := tables.Resolve(context.Background(), 100, 200)
+	require.NoError(t, err)
+	require.Equal(t, map[uint32]string{100: "public.users", 200: "public.addresses"}, names)
+	require.Nil(t, q.args)
+
+	// unknown tables are looked up with the context of the option
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	src = newFakeRows([]string{"id"}, []interface{}{int64(1)})
+	src.fields[0].TableOID = 400
+	err = NewScanner(src, ResolveTables(ctx, tables), MatchAllColumns(false)).Scan(&user)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_rows_ResolveTables_Schemas(t *testing.T) {
+	type (
+		Address struct {
+			ID   int64
+			City string
+		}
+		User struct {
+			ID       int64
+			Address  *Address `db:"address,table=addresses"`
+			Archived *Address `db:"archived,table=archive.addresses"`
+		}
+	)
+	// the columns of archive.addresses, which is not in the search path, do
+	// not go to the field of the unqualified addresses table
+	newRows := func() *fakeRows {
+		src := newFakeRows([]string{"id", "id", "city", "id", "city"},
+			[]interface{}{int64(1), int64(2), "city02", int64(3), "city03"},
+		)
+		for i, oid := range []uint32{100, 300, 300, 200, 200} {
+			src.fields[i].TableOID = oid
+		}
+		return src
+	}
+	tables := NewTableResolver(&fakeQuerier{rows: newTableRows()})
+	require.NoError(t, tables.Preload(context.Background()))
+
+	var user User
+	require.NoError(t, NewScanner(newRows(), ResolveTables(context.Background(), tables)).Scan(&user))
+	require.Equal(t, User{ID: 1, Address: &Address{ID: 3, City: "city03"}, Archived: &Address{ID: 2, City: "city02"}}, user)
+}
+
+// newTableRows returns the rows of the tables looked up by a TableResolver.
+func newTableRows() *fakeRows {
+	return newFakeRows([]string{"oid", "nspname", "relname", "visible"},
+		[]interface{}{int64(100), "public", "users", true},
+		[]interface{}{int64(200), "public", "addresses", true},
+		[]interface{}{int64(300), "archive", "addresses", false},
+	)
+}